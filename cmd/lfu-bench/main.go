@@ -0,0 +1,151 @@
+// Command lfu-bench generates a synthetic workload against the in-process LFU cache
+// and reports throughput, latency percentiles and hit ratio.
+//
+// Reads that miss load the key into the cache (read-through), writes always Put.
+// With -concurrency above 1 the cache is guarded by a single mutex, since it is not
+// safe for concurrent use on its own.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"lfucache/internal/lfu"
+	"lfucache/internal/workload"
+)
+
+type config struct {
+	capacity    int
+	ops         int
+	concurrency int
+	reads       float64
+	workload    workload.Config
+}
+
+type result struct {
+	hits      int
+	misses    int
+	latencies []time.Duration
+}
+
+func main() {
+	var cfg config
+	flag.IntVar(&cfg.capacity, "capacity", 10_000, "cache capacity")
+	flag.IntVar(&cfg.workload.Keys, "keys", 100_000, "number of distinct keys")
+	flag.IntVar(&cfg.ops, "ops", 1_000_000, "total number of operations")
+	flag.IntVar(&cfg.concurrency, "concurrency", 1, "number of concurrent workers")
+	flag.Float64Var(&cfg.reads, "reads", 0.9, "fraction of operations that are reads")
+	dist := flag.String("dist", "zipf", "key distribution: uniform, zipf or hotspot")
+	flag.Float64Var(&cfg.workload.ZipfS, "zipf-s", 1.1, "zipf skew, must be > 1")
+	flag.Float64Var(&cfg.workload.HotKeys, "hot-keys", 0.1, "hotspot: fraction of keys that are hot")
+	flag.Float64Var(&cfg.workload.HotProb, "hot-prob", 0.9, "hotspot: probability of accessing a hot key")
+	flag.IntVar(&cfg.workload.ShiftEvery, "shift-every", 0, "shift the working set every n accesses, 0 disables")
+	flag.IntVar(&cfg.workload.ShiftBy, "shift-by", 1_000, "number of keys the working set shifts by")
+	flag.IntVar(&cfg.workload.ScanEvery, "scan-every", 0, "start a sequential scan every n accesses, 0 disables")
+	flag.IntVar(&cfg.workload.ScanLength, "scan-length", 1_000, "number of keys in a scan")
+	flag.Uint64Var(&cfg.workload.Seed, "seed", 1, "random seed")
+	flag.Parse()
+	cfg.workload.Distribution = workload.Distribution(*dist)
+
+	if err := run(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, "lfu-bench:", err)
+		os.Exit(1)
+	}
+}
+
+func run(cfg config) error {
+	if cfg.ops <= 0 || cfg.concurrency <= 0 {
+		return fmt.Errorf("ops and concurrency must be positive")
+	}
+
+	cache, err := lfu.NewWithError(lfu.WithCapacity[int, int](cfg.capacity))
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	results := make([]result, cfg.concurrency)
+	var wg sync.WaitGroup
+
+	start := time.Now()
+	for w := range cfg.concurrency {
+		workerCfg := cfg.workload
+		workerCfg.Seed += uint64(w)
+		gen, err := workload.New(workerCfg)
+		if err != nil {
+			return err
+		}
+
+		ops := cfg.ops / cfg.concurrency
+		if w < cfg.ops%cfg.concurrency {
+			ops++
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(cfg.workload.Seed, uint64(w)))
+			res := result{latencies: make([]time.Duration, 0, ops)}
+
+			for range ops {
+				key := gen.Next()
+				opStart := time.Now()
+				mu.Lock()
+				if rng.Float64() < cfg.reads {
+					if _, err := cache.Get(key); err != nil {
+						res.misses++
+						cache.Put(key, key)
+					} else {
+						res.hits++
+					}
+				} else {
+					cache.Put(key, key)
+				}
+				mu.Unlock()
+				res.latencies = append(res.latencies, time.Since(opStart))
+			}
+
+			results[w] = res
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	report(cfg, results, elapsed)
+	return nil
+}
+
+func report(cfg config, results []result, elapsed time.Duration) {
+	var hits, misses int
+	latencies := make([]time.Duration, 0, cfg.ops)
+	for _, res := range results {
+		hits += res.hits
+		misses += res.misses
+		latencies = append(latencies, res.latencies...)
+	}
+	slices.Sort(latencies)
+
+	fmt.Printf("workload:    %s, %d keys, %.0f%% reads, %d workers\n",
+		cfg.workload.Distribution, cfg.workload.Keys, cfg.reads*100, cfg.concurrency)
+	fmt.Printf("cache:       capacity %d\n", cfg.capacity)
+	fmt.Printf("throughput:  %.0f ops/s (%d ops in %v)\n",
+		float64(cfg.ops)/elapsed.Seconds(), cfg.ops, elapsed.Round(time.Millisecond))
+	fmt.Printf("latency:     p50 %v  p90 %v  p99 %v  p99.9 %v  max %v\n",
+		percentile(latencies, 0.5), percentile(latencies, 0.9), percentile(latencies, 0.99),
+		percentile(latencies, 0.999), latencies[len(latencies)-1])
+	if hits+misses > 0 {
+		fmt.Printf("hit ratio:   %.2f%% (%d hits, %d misses)\n",
+			float64(hits)*100/float64(hits+misses), hits, misses)
+	}
+}
+
+// percentile returns the p-th percentile of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted)-1) * p)
+	return sorted[idx]
+}
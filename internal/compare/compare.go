@@ -0,0 +1,110 @@
+package compare
+
+import (
+	"iter"
+	"strconv"
+	"sync"
+
+	"lfucache/internal/lfu"
+)
+
+// batchSize represents the number of accesses handed to the shadow caches at once.
+const batchSize = 4096
+
+// Candidate represents a named cache taking part in a comparison.
+// Only keys are replayed, so the cache stores empty values.
+type Candidate[K comparable] struct {
+	Name  string
+	Cache lfu.Cache[K, struct{}]
+}
+
+// Result represents the outcome of replaying the accesses through one candidate.
+type Result struct {
+	Name   string
+	Hits   int
+	Misses int
+}
+
+// HitRatio returns the share of accesses that hit, or 0 if there were none.
+func (r Result) HitRatio() float64 {
+	if r.Hits+r.Misses == 0 {
+		return 0
+	}
+
+	return float64(r.Hits) / float64(r.Hits+r.Misses)
+}
+
+// LFU returns an LFU candidate named "lfu-<capacity>" for every capacity.
+func LFU[K comparable](capacities ...int) []Candidate[K] {
+	candidates := make([]Candidate[K], 0, len(capacities))
+	for _, capacity := range capacities {
+		candidates = append(candidates, Candidate[K]{
+			Name:  "lfu-" + strconv.Itoa(capacity),
+			Cache: lfu.New[K, struct{}](capacity),
+		})
+	}
+
+	return candidates
+}
+
+// Run replays the accesses, e.g. a parsed trace or a generated workload, through every
+// candidate and returns their results in the order of the candidates. Each access is
+// a Get, and a miss loads the key with Put, as a read-through cache would.
+//
+// The accesses are read once; every candidate runs in its own goroutine,
+// so the candidates must not share a cache.
+func Run[K comparable](accesses iter.Seq[K], candidates ...Candidate[K]) []Result {
+	results := make([]Result, len(candidates))
+	batches := make([]chan []K, len(candidates))
+	var wg sync.WaitGroup
+
+	for i, candidate := range candidates {
+		batches[i] = make(chan []K, 1)
+		results[i].Name = candidate.Name
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			replay(candidate.Cache, batches[i], &results[i])
+		}()
+	}
+
+	batch := make([]K, 0, batchSize)
+	for key := range accesses {
+		batch = append(batch, key)
+		if len(batch) == batchSize {
+			broadcast(batches, batch)
+			batch = make([]K, 0, batchSize)
+		}
+	}
+	if len(batch) > 0 {
+		broadcast(batches, batch)
+	}
+
+	for _, ch := range batches {
+		close(ch)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func broadcast[K comparable](batches []chan []K, batch []K) {
+	for _, ch := range batches {
+		ch <- batch
+	}
+}
+
+func replay[K comparable](cache lfu.Cache[K, struct{}], batches <-chan []K, result *Result) {
+	for batch := range batches {
+		for _, key := range batch {
+			if _, err := cache.Get(key); err == nil {
+				result.Hits++
+				continue
+			}
+
+			result.Misses++
+			cache.Put(key, struct{}{})
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package compare
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"lfucache/internal/lfu"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	trace := make([]int, 0, 10_000)
+	for i := range 10_000 {
+		trace = append(trace, i%10)
+	}
+
+	candidates := append(LFU[int](5, 10), Candidate[int]{Name: "nop", Cache: lfu.NewNop[int, struct{}]()})
+	results := Run(slices.Values(trace), candidates...)
+
+	require.Equal(t, []string{"lfu-5", "lfu-10", "nop"}, []string{results[0].Name, results[1].Name, results[2].Name})
+	require.Equal(t, Result{Name: "lfu-10", Hits: 9_990, Misses: 10}, results[1])
+	require.Equal(t, Result{Name: "nop", Misses: 10_000}, results[2])
+	require.Equal(t, 10_000, results[0].Hits+results[0].Misses)
+	require.Less(t, results[0].HitRatio(), results[1].HitRatio())
+	require.Zero(t, results[2].HitRatio())
+}
+
+func TestRunWithoutAccesses(t *testing.T) {
+	t.Parallel()
+
+	results := Run(slices.Values([]string(nil)), LFU[string](1)...)
+	require.Equal(t, []Result{{Name: "lfu-1"}}, results)
+	require.Zero(t, results[0].HitRatio())
+}
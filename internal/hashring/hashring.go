@@ -0,0 +1,173 @@
+package hashring
+
+import (
+	"errors"
+	"hash/fnv"
+	"slices"
+	"strconv"
+)
+
+var ErrEmptyRing = errors.New("ring has no members")
+
+// DefaultReplicas represents the default number of virtual nodes placed on the ring
+// per unit of member weight.
+const DefaultReplicas = 100
+
+// Ring represents a consistent-hash ring with virtual nodes and weighted members.
+// Each member occupies replicas*weight points on the ring, so a member with weight 2
+// owns roughly twice as many keys as a member with weight 1.
+type Ring struct {
+	replicas int
+	members  map[string]int    // member -> weight
+	owners   map[uint64]string // virtual node hash -> member
+	points   []uint64          // sorted virtual node hashes
+}
+
+// New initializes the ring with the specified number of virtual nodes per unit of weight.
+// If no value is provided, it defaults to DefaultReplicas.
+//
+// Arguments:
+//   - replicas: Optional integer specifying the number of virtual nodes per unit of weight.
+//     Must be a positive number if provided.
+//
+// Returns:
+//   - A pointer to a new, empty Ring instance.
+func New(replicas ...int) *Ring {
+	resultReplicas := DefaultReplicas
+	if len(replicas) > 0 {
+		if replicas[0] <= 0 {
+			panic("Replicas must be positive.")
+		}
+		resultReplicas = replicas[0]
+	}
+
+	return &Ring{
+		replicas: resultReplicas,
+		members:  make(map[string]int),
+		owners:   make(map[uint64]string),
+	}
+}
+
+// Add places the member on the ring with the given weight (1 if omitted).
+// Adding an existing member updates its weight.
+//
+// O(members * replicas * log(members * replicas))
+func (r *Ring) Add(member string, weight ...int) {
+	resultWeight := 1
+	if len(weight) > 0 {
+		if weight[0] <= 0 {
+			panic("Weight must be positive.")
+		}
+		resultWeight = weight[0]
+	}
+
+	r.members[member] = resultWeight
+	r.rebuild()
+}
+
+// Remove takes the member off the ring. Keys it owned move to the next members clockwise.
+//
+// O(members * replicas * log(members * replicas))
+func (r *Ring) Remove(member string) {
+	if _, exists := r.members[member]; !exists {
+		return
+	}
+
+	delete(r.members, member)
+	r.rebuild()
+}
+
+// Members returns the ring members in lexicographic order.
+//
+// O(members * log(members))
+func (r *Ring) Members() []string {
+	members := make([]string, 0, len(r.members))
+	for member := range r.members {
+		members = append(members, member)
+	}
+	slices.Sort(members)
+
+	return members
+}
+
+// Len returns the number of members on the ring.
+//
+// O(1)
+func (r *Ring) Len() int {
+	return len(r.members)
+}
+
+// Owner returns the member responsible for the key if the ring is not empty,
+// otherwise, returns ErrEmptyRing.
+//
+// O(log(members * replicas))
+func (r *Ring) Owner(key string) (string, error) {
+	if len(r.points) == 0 {
+		return "", ErrEmptyRing
+	}
+
+	return r.owners[r.points[r.search(hashKey(key))]], nil
+}
+
+// OwnersN returns up to n distinct members responsible for the key, starting with
+// its primary owner and walking the ring clockwise. Fewer than n members are returned
+// if the ring does not have that many.
+//
+// O(log(members * replicas) + members * replicas) in the worst case.
+func (r *Ring) OwnersN(key string, n int) []string {
+	n = min(n, len(r.members))
+	if n <= 0 {
+		return nil
+	}
+
+	owners := make([]string, 0, n)
+	start := r.search(hashKey(key))
+	for i := 0; i < len(r.points) && len(owners) < n; i++ {
+		member := r.owners[r.points[(start+i)%len(r.points)]]
+		if !slices.Contains(owners, member) {
+			owners = append(owners, member)
+		}
+	}
+
+	return owners
+}
+
+// search returns the index of the first virtual node clockwise from the hash,
+// wrapping around to the beginning of the ring.
+func (r *Ring) search(hash uint64) int {
+	idx, _ := slices.BinarySearch(r.points, hash)
+	if idx == len(r.points) {
+		return 0
+	}
+
+	return idx
+}
+
+// rebuild recomputes all virtual nodes from the current members.
+// On the (unlikely) hash collision between two virtual nodes the lexicographically
+// smaller member wins, so the ring layout does not depend on insertion order.
+func (r *Ring) rebuild() {
+	clear(r.owners)
+	r.points = r.points[:0]
+
+	for member, weight := range r.members {
+		for i := 0; i < r.replicas*weight; i++ {
+			hash := hashKey(member + "#" + strconv.Itoa(i))
+			if owner, exists := r.owners[hash]; exists && owner < member {
+				continue
+			}
+			r.owners[hash] = member
+		}
+	}
+
+	for hash := range r.owners {
+		r.points = append(r.points, hash)
+	}
+	slices.Sort(r.points)
+}
+
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum64()
+}
@@ -0,0 +1,115 @@
+package hashring
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnerOnEmptyRing(t *testing.T) {
+	t.Parallel()
+
+	ring := New()
+
+	_, err := ring.Owner("key")
+	require.ErrorIs(t, err, ErrEmptyRing)
+	require.Empty(t, ring.OwnersN("key", 3))
+}
+
+func TestOwnerIsStable(t *testing.T) {
+	t.Parallel()
+
+	first := New()
+	second := New()
+	for _, member := range []string{"a", "b", "c"} {
+		first.Add(member)
+	}
+	for _, member := range []string{"c", "a", "b"} {
+		second.Add(member)
+	}
+
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(i)
+
+		owner, err := first.Owner(key)
+		require.NoError(t, err)
+
+		other, err := second.Owner(key)
+		require.NoError(t, err)
+		require.Equal(t, owner, other)
+	}
+}
+
+func TestRemoveMovesOnlyRemovedKeys(t *testing.T) {
+	t.Parallel()
+
+	ring := New()
+	ring.Add("a")
+	ring.Add("b")
+	ring.Add("c")
+
+	before := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(i)
+		before[key], _ = ring.Owner(key)
+	}
+
+	ring.Remove("b")
+	require.Equal(t, []string{"a", "c"}, ring.Members())
+
+	for key, owner := range before {
+		after, err := ring.Owner(key)
+		require.NoError(t, err)
+		if owner != "b" {
+			require.Equal(t, owner, after)
+		} else {
+			require.NotEqual(t, "b", after)
+		}
+	}
+}
+
+func TestWeightedDistribution(t *testing.T) {
+	t.Parallel()
+
+	ring := New()
+	ring.Add("light")
+	ring.Add("heavy", 3)
+
+	counts := make(map[string]int)
+	for i := 0; i < 10_000; i++ {
+		owner, err := ring.Owner(strconv.Itoa(i))
+		require.NoError(t, err)
+		counts[owner]++
+	}
+
+	ratio := float64(counts["heavy"]) / float64(counts["light"])
+	require.InDelta(t, 3., ratio, 1.)
+}
+
+func TestOwnersN(t *testing.T) {
+	t.Parallel()
+
+	ring := New()
+	ring.Add("a")
+	ring.Add("b")
+	ring.Add("c")
+
+	owners := ring.OwnersN("key", 2)
+	require.Len(t, owners, 2)
+	require.NotEqual(t, owners[0], owners[1])
+
+	primary, err := ring.Owner("key")
+	require.NoError(t, err)
+	require.Equal(t, primary, owners[0])
+
+	require.Len(t, ring.OwnersN("key", 10), 3)
+}
+
+func TestInvalidWeightPanics(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() {
+		New().Add("a", 0)
+	})
+}
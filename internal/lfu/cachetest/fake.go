@@ -0,0 +1,159 @@
+package cachetest
+
+import (
+	"iter"
+	"slices"
+
+	"lfucache/internal/lfu"
+)
+
+// Call represents a recorded call to a Fake method together with its key.
+// Calls without a key, such as Size, record the zero key.
+type Call[K comparable] struct {
+	Method string
+	Key    K
+}
+
+// Fake represents an in-memory lfu.Cache for testing code that depends on a cache.
+// It never evicts on its own: entries disappear only when the test scripts it with Evict,
+// so results do not depend on the eviction policy. Lookups can be forced to miss,
+// and every call is recorded.
+type Fake[K comparable, V any] struct {
+	capacity    int
+	values      map[K]V
+	frequencies map[K]int
+	order       []K // insertion order, used to break frequency ties in All
+	forcedMiss  map[K]bool
+	calls       []Call[K]
+}
+
+// NewFake initializes an empty fake reporting the given capacity.
+// The capacity is only returned by Capacity and is not enforced.
+//
+// Returns:
+//   - A pointer to a new Fake instance.
+func NewFake[K comparable, V any](capacity int) *Fake[K, V] {
+	return &Fake[K, V]{
+		capacity:    capacity,
+		values:      make(map[K]V),
+		frequencies: make(map[K]int),
+		forcedMiss:  make(map[K]bool),
+	}
+}
+
+// Get returns the value of the key if it is present and not forced to miss,
+// otherwise, returns lfu.ErrKeyNotFound.
+func (f *Fake[K, V]) Get(key K) (V, error) {
+	f.record("Get", key)
+
+	value, exists := f.values[key]
+	if !exists || f.forcedMiss[key] {
+		var zeroVal V
+		return zeroVal, lfu.ErrKeyNotFound
+	}
+
+	f.frequencies[key]++
+	return value, nil
+}
+
+// Put updates the value of the key if present, or inserts the key if not already present.
+func (f *Fake[K, V]) Put(key K, value V) {
+	f.record("Put", key)
+
+	if _, exists := f.values[key]; !exists {
+		f.order = append(f.order, key)
+	}
+	f.values[key] = value
+	f.frequencies[key]++
+}
+
+// All returns the iterator in descending order of frequencies.
+// Keys with the same frequency are listed in insertion order.
+func (f *Fake[K, V]) All() iter.Seq2[K, V] {
+	var zeroKey K
+	f.record("All", zeroKey)
+
+	keys := slices.Clone(f.order)
+	slices.SortStableFunc(keys, func(a, b K) int {
+		return f.frequencies[b] - f.frequencies[a]
+	})
+
+	return func(yield func(K, V) bool) {
+		for _, key := range keys {
+			value, exists := f.values[key]
+			if exists && !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// Size returns the number of entries, including the ones forced to miss.
+func (f *Fake[K, V]) Size() int {
+	var zeroKey K
+	f.record("Size", zeroKey)
+
+	return len(f.values)
+}
+
+// Capacity returns the capacity passed to NewFake.
+func (f *Fake[K, V]) Capacity() int {
+	var zeroKey K
+	f.record("Capacity", zeroKey)
+
+	return f.capacity
+}
+
+// GetKeyFrequency returns the number of Get hits and Puts of the key if it is present
+// and not forced to miss, otherwise, returns lfu.ErrKeyNotFound.
+func (f *Fake[K, V]) GetKeyFrequency(key K) (int, error) {
+	f.record("GetKeyFrequency", key)
+
+	if _, exists := f.values[key]; !exists || f.forcedMiss[key] {
+		return 0, lfu.ErrKeyNotFound
+	}
+
+	return f.frequencies[key], nil
+}
+
+// ForceMiss makes lookups of the keys miss until AllowHit is called for them.
+// The entries are kept and reappear once allowed again.
+func (f *Fake[K, V]) ForceMiss(keys ...K) {
+	for _, key := range keys {
+		f.forcedMiss[key] = true
+	}
+}
+
+// AllowHit undoes ForceMiss for the keys.
+func (f *Fake[K, V]) AllowHit(keys ...K) {
+	for _, key := range keys {
+		delete(f.forcedMiss, key)
+	}
+}
+
+// Evict removes the keys as if the cache had evicted them.
+func (f *Fake[K, V]) Evict(keys ...K) {
+	for _, key := range keys {
+		if _, exists := f.values[key]; !exists {
+			continue
+		}
+
+		delete(f.values, key)
+		delete(f.frequencies, key)
+		f.order = slices.DeleteFunc(f.order, func(k K) bool { return k == key })
+	}
+}
+
+// Calls returns the recorded calls in the order they were made.
+func (f *Fake[K, V]) Calls() []Call[K] {
+	return slices.Clone(f.calls)
+}
+
+// ResetCalls forgets the recorded calls.
+func (f *Fake[K, V]) ResetCalls() {
+	f.calls = nil
+}
+
+func (f *Fake[K, V]) record(method string, key K) {
+	f.calls = append(f.calls, Call[K]{Method: method, Key: key})
+}
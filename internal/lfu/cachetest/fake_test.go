@@ -0,0 +1,81 @@
+package cachetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"lfucache/internal/lfu"
+)
+
+// must compile
+func testImplements[K comparable, V any]() lfu.Cache[K, V] {
+	return NewFake[K, V](1)
+}
+
+func TestFakeBehavesLikeCache(t *testing.T) {
+	t.Parallel()
+
+	cache := NewFake[string, int](2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	_, _ = cache.Get("b")
+
+	require.Equal(t, 3, cache.Size())
+	require.Equal(t, 2, cache.Capacity())
+
+	value, err := cache.Get("c")
+	require.NoError(t, err)
+	require.Equal(t, 3, value)
+
+	freq, err := cache.GetKeyFrequency("b")
+	require.NoError(t, err)
+	require.Equal(t, 2, freq)
+
+	var keys []string
+	for key := range cache.All() {
+		keys = append(keys, key)
+	}
+	require.Equal(t, []string{"b", "c", "a"}, keys)
+}
+
+func TestFakeForcedMissesAndEvictions(t *testing.T) {
+	t.Parallel()
+
+	cache := NewFake[string, int](10)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	cache.ForceMiss("a")
+	_, err := cache.Get("a")
+	require.ErrorIs(t, err, lfu.ErrKeyNotFound)
+
+	cache.AllowHit("a")
+	value, err := cache.Get("a")
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	cache.Evict("b", "missing")
+	_, err = cache.GetKeyFrequency("b")
+	require.ErrorIs(t, err, lfu.ErrKeyNotFound)
+	require.Equal(t, 1, cache.Size())
+}
+
+func TestFakeRecordsCalls(t *testing.T) {
+	t.Parallel()
+
+	cache := NewFake[string, int](1)
+	cache.Put("a", 1)
+	_, _ = cache.Get("b")
+	_ = cache.Size()
+
+	require.Equal(t, []Call[string]{
+		{Method: "Put", Key: "a"},
+		{Method: "Get", Key: "b"},
+		{Method: "Size"},
+	}, cache.Calls())
+
+	cache.ResetCalls()
+	require.Empty(t, cache.Calls())
+}
@@ -0,0 +1,55 @@
+package lfu
+
+import (
+	"io"
+	"sync"
+)
+
+// asyncCloseQueue represents how many values may wait for the asynchronous closer.
+const asyncCloseQueue = 1024
+
+// asyncCloser closes values in a single background goroutine, which runs only while
+// values are queued. Once asyncCloseQueue values are waiting, further values are closed
+// by the caller, so a slow Close slows the cache down instead of piling up.
+type asyncCloser struct {
+	mu      sync.Mutex
+	queue   []io.Closer
+	running bool
+}
+
+// close queues the value, starting the background goroutine if it is not running.
+func (c *asyncCloser) close(closer io.Closer) {
+	c.mu.Lock()
+	if len(c.queue) >= asyncCloseQueue {
+		c.mu.Unlock()
+		_ = closer.Close()
+		return
+	}
+
+	c.queue = append(c.queue, closer)
+	if !c.running {
+		c.running = true
+		go c.run()
+	}
+	c.mu.Unlock()
+}
+
+// run closes the queued values in order and exits once the queue is empty.
+func (c *asyncCloser) run() {
+	for {
+		c.mu.Lock()
+		if len(c.queue) == 0 {
+			c.running = false
+			c.queue = nil
+			c.mu.Unlock()
+			return
+		}
+
+		closer := c.queue[0]
+		c.queue[0] = nil
+		c.queue = c.queue[1:]
+		c.mu.Unlock()
+
+		_ = closer.Close()
+	}
+}
@@ -0,0 +1,126 @@
+package lfu
+
+// compositeKey represents the key of a Cache2 entry.
+type compositeKey[K1, K2 comparable] struct {
+	first  K1
+	second K2
+}
+
+// Cache2 represents an LFU cache keyed by two-part keys such as (tenant, object ID).
+// All entries sharing the first part can be invalidated at once in time proportional
+// to their number rather than to the cache size.
+// Eviction works exactly as in the single-key cache.
+type Cache2[K1, K2 comparable, V any] struct {
+	cache *cacheImpl[compositeKey[K1, K2], V]
+	index map[K1]map[K2]struct{} // first key part -> second key parts present in the cache
+}
+
+// NewCache2 initializes the cache with the given capacity.
+// If no value is provided, it defaults to DefaultCapacity.
+//
+// Returns:
+//   - A pointer to a new Cache2 instance.
+func NewCache2[K1, K2 comparable, V any](capacity ...int) *Cache2[K1, K2, V] {
+	return &Cache2[K1, K2, V]{
+		cache: New[compositeKey[K1, K2], V](capacity...),
+		index: make(map[K1]map[K2]struct{}),
+	}
+}
+
+// Get returns the value of the key if the key exists in the cache,
+// otherwise, returns ErrKeyNotFound.
+//
+// O(1)
+func (c *Cache2[K1, K2, V]) Get(k1 K1, k2 K2) (V, error) {
+	return c.cache.Get(compositeKey[K1, K2]{first: k1, second: k2})
+}
+
+// Put updates the value of the key if present, or inserts the key if not already present,
+// evicting the least frequently used key when the cache is full.
+//
+// O(1)
+func (c *Cache2[K1, K2, V]) Put(k1 K1, k2 K2, value V) {
+	key := compositeKey[K1, K2]{first: k1, second: k2}
+	evicted, replaced := c.cache.put(key, value)
+	if evicted.ok {
+		c.unindex(evicted.key)
+		c.cache.drop(evicted.value, evicted.ref)
+	}
+	if replaced.ok {
+		c.cache.dropReplaced(replaced.value, value, replaced.ref)
+		return
+	}
+
+	if _, exists := c.cache.mp[key]; exists {
+		seconds, exists := c.index[k1]
+		if !exists {
+			seconds = make(map[K2]struct{})
+			c.index[k1] = seconds
+		}
+		seconds[k2] = struct{}{}
+	}
+}
+
+// Delete removes the key and reports whether it was present.
+//
+// O(1)
+func (c *Cache2[K1, K2, V]) Delete(k1 K1, k2 K2) bool {
+	cached, exists := c.cache.mp[compositeKey[K1, K2]{first: k1, second: k2}]
+	if !exists {
+		return false
+	}
+
+	c.delete(cached)
+	return true
+}
+
+// InvalidateAll removes all entries whose key starts with k1 and returns their number.
+//
+// O(number of removed entries)
+func (c *Cache2[K1, K2, V]) InvalidateAll(k1 K1) int {
+	seconds := c.index[k1]
+	removed := len(seconds)
+	for k2 := range seconds {
+		c.delete(c.cache.mp[compositeKey[K1, K2]{first: k1, second: k2}])
+	}
+
+	return removed
+}
+
+// GetKeyFrequency returns the element's frequency if the key exists in the cache,
+// otherwise, returns ErrKeyNotFound.
+//
+// O(1)
+func (c *Cache2[K1, K2, V]) GetKeyFrequency(k1 K1, k2 K2) (int, error) {
+	return c.cache.GetKeyFrequency(compositeKey[K1, K2]{first: k1, second: k2})
+}
+
+// Size returns the cache size.
+//
+// O(1)
+func (c *Cache2[K1, K2, V]) Size() int {
+	return c.cache.Size()
+}
+
+// Capacity returns the cache capacity.
+//
+// O(1)
+func (c *Cache2[K1, K2, V]) Capacity() int {
+	return c.cache.Capacity()
+}
+
+// delete removes the entry from the cache and the index, dropping its value.
+func (c *Cache2[K1, K2, V]) delete(cached *cacheNode[compositeKey[K1, K2], V]) {
+	gone := c.cache.remove(cached)
+	c.unindex(gone.key)
+	c.cache.drop(gone.value, gone.ref)
+}
+
+// unindex removes the key from the index, dropping the first part once it has no entries left.
+func (c *Cache2[K1, K2, V]) unindex(key compositeKey[K1, K2]) {
+	seconds := c.index[key.first]
+	delete(seconds, key.second)
+	if len(seconds) == 0 {
+		delete(c.index, key.first)
+	}
+}
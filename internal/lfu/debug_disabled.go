@@ -0,0 +1,6 @@
+//go:build !lfudebug
+
+package lfu
+
+// debugChecks enables invariant validation after every mutation.
+const debugChecks = false
@@ -0,0 +1,80 @@
+package lfu
+
+// FrequencyCount represents the number of entries sharing one access frequency.
+type FrequencyCount struct {
+	Frequency int
+	Count     int
+}
+
+// GetKeyFrequencies returns the frequencies of the keys present in the cache.
+// Missing keys are omitted from the result. Like GetKeyFrequency, it does not affect frequencies.
+//
+// O(len(keys))
+func (l *cacheImpl[K, V]) GetKeyFrequencies(keys ...K) map[K]int {
+	frequencies := make(map[K]int, len(keys))
+	for _, key := range keys {
+		if cached, exists := l.mp[key]; exists {
+			frequencies[key] = cached.baseNode.Key
+		}
+	}
+
+	return frequencies
+}
+
+// FrequencyHistogram returns the number of entries per frequency level in ascending order
+// of frequency. Levels without entries are omitted.
+// A histogram consisting of frequency 1 only means the cache is churning.
+//
+// O(number of distinct frequencies)
+func (l *cacheImpl[K, V]) FrequencyHistogram() []FrequencyCount {
+	histogram := make([]FrequencyCount, 0, l.frequencies.Len())
+	for freq, bucket := range l.frequencies.All() {
+		histogram = append(histogram, FrequencyCount{Frequency: freq, Count: bucket.Len()})
+	}
+
+	return histogram
+}
+
+// TopK returns up to k most frequently used entries in the order of All:
+// descending frequency, most recently used first among equal frequencies.
+//
+// O(k + number of distinct frequencies)
+func (l *cacheImpl[K, V]) TopK(k int) []Entry[K, V] {
+	if k <= 0 {
+		return nil
+	}
+
+	entries := make([]Entry[K, V], 0, min(k, l.Size()))
+	for freq, bucket := range l.frequencies.Backward() {
+		for key, value := range bucket.All() {
+			if len(entries) == k {
+				return entries
+			}
+			entries = append(entries, Entry[K, V]{Key: key, Value: value, Frequency: freq})
+		}
+	}
+
+	return entries
+}
+
+// BottomK returns up to k least frequently used entries in eviction order:
+// ascending frequency, least recently used first among equal frequencies.
+//
+// O(k + number of distinct frequencies)
+func (l *cacheImpl[K, V]) BottomK(k int) []Entry[K, V] {
+	if k <= 0 {
+		return nil
+	}
+
+	entries := make([]Entry[K, V], 0, min(k, l.Size()))
+	for freq, bucket := range l.frequencies.All() {
+		for key, value := range bucket.Backward() {
+			if len(entries) == k {
+				return entries
+			}
+			entries = append(entries, Entry[K, V]{Key: key, Value: value, Frequency: freq})
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,59 @@
+package lfu
+
+// valueRef counts the handles held for a single cached value.
+type valueRef[V any] struct {
+	value   V
+	refs    int
+	dropped bool // the cache no longer references the value
+	release func(V)
+}
+
+// Handle holds a reference to a cached value acquired with Acquire.
+// While a handle is held, the value is not closed even if the cache evicts, replaces
+// or deletes it; closing is deferred until the last handle is released.
+// Like the cache itself, handles are not safe for concurrent use.
+type Handle[V any] struct {
+	ref      *valueRef[V]
+	released bool
+}
+
+// Value returns the referenced value. It stays valid until Release is called.
+func (h *Handle[V]) Value() V {
+	return h.ref.value
+}
+
+// Release gives up the reference. If the cache has already dropped the value and this was
+// the last handle, the value is closed as configured by WithCloseOnEvict.
+// Releasing a handle more than once has no effect.
+func (h *Handle[V]) Release() {
+	if h.released {
+		return
+	}
+	h.released = true
+
+	h.ref.refs--
+	if h.ref.refs == 0 && h.ref.dropped {
+		h.ref.release(h.ref.value)
+	}
+}
+
+// Acquire returns a handle to the value of the key if the key exists in the cache,
+// otherwise, returns ErrKeyNotFound. Acquiring counts as an access, just like Get.
+//
+// O(1)
+func (l *cacheImpl[K, V]) Acquire(key K) (*Handle[V], error) {
+	cached, exists := l.mp[key]
+	if !exists {
+		l.stats.Misses++
+		return nil, ErrKeyNotFound
+	}
+
+	l.stats.Hits++
+	_ = l.hangUpNode(cached)
+	if cached.ref == nil {
+		cached.ref = &valueRef[V]{value: cached.node.Value, release: l.closeValue}
+	}
+	cached.ref.refs++
+
+	return &Handle[V]{ref: cached.ref}, nil
+}
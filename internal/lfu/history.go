@@ -0,0 +1,113 @@
+package lfu
+
+import (
+	"context"
+	"maps"
+)
+
+// SnapshotFrequencies returns the frequencies of all keys in the cache, without their values,
+// together with loaded frequencies that have not been used yet and those of soft-deleted keys. The snapshot can be persisted
+// and passed to LoadFrequencies after a restart, so the cache warms up with its historical
+// frequencies instead of starting every key at 1.
+//
+// O(size + number of pending frequencies)
+func (l *cacheImpl[K, V]) SnapshotFrequencies() map[K]int {
+	snapshot, _ := l.SnapshotFrequenciesCtx(context.Background())
+	return snapshot
+}
+
+// SnapshotFrequenciesCtx works like SnapshotFrequencies but gives up once the context
+// is cancelled, returning nil and the context's error.
+//
+// O(size + number of pending frequencies)
+func (l *cacheImpl[K, V]) SnapshotFrequenciesCtx(ctx context.Context) (map[K]int, error) {
+	snapshot := maps.Clone(l.history)
+	if snapshot == nil {
+		snapshot = make(map[K]int, len(l.mp))
+	}
+
+	now := l.clock()
+	for key, node := range l.tombstones {
+		if now.Before(node.Value.deadline) {
+			snapshot[key] = node.Value.freq
+		}
+	}
+
+	for key, cached := range l.mp {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		snapshot[key] = cached.baseNode.Key
+	}
+
+	return snapshot, nil
+}
+
+// LoadFrequencies remembers the frequencies, typically a snapshot taken by SnapshotFrequencies.
+// A key inserted later by Put starts with its remembered frequency instead of 1, and the
+// remembered frequency is then forgotten. Keys already in the cache keep their frequency.
+// Frequencies below 2 are ignored because every key starts at 1 anyway.
+//
+// Remembered frequencies are kept until their keys are inserted, so loading a snapshot
+// much larger than the capacity costs memory proportional to the snapshot.
+//
+// O(len(frequencies))
+func (l *cacheImpl[K, V]) LoadFrequencies(frequencies map[K]int) {
+	for key, freq := range frequencies {
+		if freq < 2 {
+			continue
+		}
+		if _, exists := l.mp[key]; exists {
+			continue
+		}
+
+		if l.history == nil {
+			l.history = make(map[K]int)
+		}
+		l.history[key] = freq
+	}
+}
+
+// putWithFrequency works like Put, but a key that is not cached yet starts with the given
+// frequency. The frequency is not remembered if the key is not stored, e.g. because the Put
+// was rejected, so it cannot leak into a later unrelated Put of the key.
+func (l *cacheImpl[K, V]) putWithFrequency(key K, value V, freq int) {
+	if _, exists := l.mp[key]; exists || freq < 2 {
+		l.Put(key, value)
+		return
+	}
+
+	if l.history == nil {
+		l.history = make(map[K]int)
+	}
+	prev, remembered := l.history[key]
+	l.history[key] = freq
+	l.Put(key, value)
+
+	if _, exists := l.mp[key]; !exists {
+		if remembered {
+			l.history[key] = prev
+		} else {
+			delete(l.history, key)
+		}
+	}
+}
+
+// recall returns the frequency a newly inserted key starts with, taken from its tombstone
+// or from the loaded frequencies, and forgets it.
+func (l *cacheImpl[K, V]) recall(key K) int {
+	if len(l.tombstones) > 0 {
+		if freq := l.exhume(key); freq > 0 {
+			delete(l.history, key)
+			return freq
+		}
+	}
+
+	freq, exists := l.history[key]
+	if !exists {
+		return 1
+	}
+
+	delete(l.history, key)
+	return freq
+}
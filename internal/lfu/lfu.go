@@ -1,16 +1,28 @@
 package lfu
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"iter"
 	"lfucache/internal/linkedlist"
+	"math"
+	"time"
 )
 
-var ErrKeyNotFound = errors.New("key not found")
+var (
+	ErrKeyNotFound     = errors.New("key not found")
+	ErrInvalidFraction = errors.New("invalid fraction")
+	ErrInvalidColumn   = errors.New("invalid column")
+)
 
 // DefaultCapacity represents the default capacity of the LFU Cache
 const DefaultCapacity = 5
 
+// MaxFrequency represents the highest frequency a key can reach. Accessing a key at
+// MaxFrequency keeps its frequency and only marks it as the most recently used one.
+const MaxFrequency = math.MaxInt
+
 // Cache
 // O(capacity) memory
 type Cache[K comparable, V any] interface {
@@ -46,30 +58,70 @@ type Cache[K comparable, V any] interface {
 	Capacity() int
 
 	// GetKeyFrequency returns the element's frequencies if the key exists in the cache,
-	// otherwise, returns ErrKeyNotFound.
+	// otherwise, returns ErrKeyNotFound. Frequencies saturate at MaxFrequency.
 	//
 	// O(1)
 	GetKeyFrequency(key K) (int, error)
 }
 
+// Entry represents a cached key-value pair together with its access frequency.
+type Entry[K comparable, V any] struct {
+	Key       K   `json:"key"`
+	Value     V   `json:"value"`
+	Frequency int `json:"frequency,omitempty"`
+}
+
 type cacheNode[K comparable, V any] struct {
 	node     *linkedlist.Node[K, V]
 	baseNode *linkedlist.Node[int, *linkedlist.List[K, V]]
+	// order links the entry into the insertion list, nil unless WithInsertionOrder is set.
+	order *linkedlist.Node[K, *cacheNode[K, V]]
+	// ref tracks handles acquired for the current value, nil if there are none.
+	ref *valueRef[V]
+	// slot is the position of the entry in cacheImpl.slots, unused unless WithSampling is set.
+	slot int
+}
+
+// displaced is an entry value taken out of the cache by put or remove. It is copied out of
+// the entry node, since the node goes back to the arena as soon as the entry is removed.
+type displaced[K comparable, V any] struct {
+	key   K
+	value V
+	ref   *valueRef[V] // handles still held on the value, nil if there are none
+	ok    bool         // false if nothing was taken out
 }
 
 // cacheImpl represents LFU cache implementation
 type cacheImpl[K comparable, V any] struct {
 	capacity    int
-	frequencies linkedlist.List[int, *linkedlist.List[K, V]]
+	frequencies linkedlist.List[int, *linkedlist.List[K, V]] // buckets, allocated from their own arena
+	nodes       *linkedlist.Arena[K, V]                      // entry nodes, shared by all buckets
+	insertion   *linkedlist.List[K, *cacheNode[K, V]]        // entries oldest first, nil unless WithInsertionOrder is set
 	mp          map[K]*cacheNode[K, V]
+	slots       []*cacheNode[K, V]                            // every entry in no particular order, if sampling
+	spare       *linkedlist.Node[int, *linkedlist.List[K, V]] // emptied bucket kept for reuse
+	spareEntry  *cacheNode[K, V]                              // removed entry kept for reuse
+	history     map[K]int                                     // frequencies loaded for keys not in the cache yet
+	tombstones  map[K]*linkedlist.Node[K, tombstone]          // soft-deleted keys -> their node in graveyard
+	graveyard   *linkedlist.List[K, tombstone]                // tombstones, oldest first
+
+	sampling       bool // maintain slots so that Sample costs O(n) instead of O(size)
+	closeOnEvict   bool
+	closer         *asyncCloser // closes dropped values in the background, nil to close them in place
+	admit          func(K, V) bool
+	tombstoneGrace time.Duration
+	now            func() time.Time // clock for tombstone deadlines, time.Now if nil
+	stats          Stats
+	configErr      error // problems reported by options, only set during construction
 }
 
 // New initializes the cache with the specified capacity.
 // If no capacity is provided, it defaults to DefaultCapacity.
+// A cache with zero capacity stores nothing: Put is ignored and Get always misses.
 //
 // Arguments:
 //   - capacity: Optional integer specifying the initial capacity of the cache.
-//     Must be a positive number if provided.
+//     Must not be negative if provided.
 //
 // Returns:
 //   - A pointer to a new cacheImpl instance.
@@ -77,14 +129,15 @@ func New[K comparable, V any](capacity ...int) *cacheImpl[K, V] {
 	resultCapacity := DefaultCapacity
 	if len(capacity) > 0 {
 		if capacity[0] < 0 {
-			panic("Capacity must be positive.")
+			panic("Capacity must not be negative.")
 		}
 		resultCapacity = capacity[0]
 	}
 
 	return &cacheImpl[K, V]{
 		capacity:    resultCapacity,
-		frequencies: *linkedlist.NewList[int, *linkedlist.List[K, V]](),
+		frequencies: *linkedlist.NewListWithArena(linkedlist.NewArena[int, *linkedlist.List[K, V]]()),
+		nodes:       linkedlist.NewArena[K, V](),
 		mp:          make(map[K]*cacheNode[K, V]),
 	}
 }
@@ -96,31 +149,51 @@ func New[K comparable, V any](capacity ...int) *cacheImpl[K, V] {
 func (l *cacheImpl[K, V]) Get(key K) (V, error) {
 	node, exists := l.mp[key]
 	if !exists {
+		l.stats.Misses++
 		var zeroVal V
 		return zeroVal, ErrKeyNotFound
 	}
 
+	l.stats.Hits++
 	return l.hangUpNode(node).Value, nil
 }
 
 func (l *cacheImpl[K, V]) hangUpNode(node *cacheNode[K, V]) *linkedlist.Node[K, V] {
 	value := node.node
 	currentFreq := node.baseNode
+
+	// The frequency saturates: only refresh the recency of the node within its bucket.
+	if currentFreq.Key == MaxFrequency {
+		currentFreq.Value.MoveToFront(value)
+		l.debugValidate()
+		return value
+	}
+
 	nextFreq := currentFreq.Next()
-	value.Untie()
-	if currentFreq == l.frequencies.Last() || nextFreq.Key != currentFreq.Key+1 {
-		newList := linkedlist.NewList[K, V]()
-		newList.AddFrontOrAfter(value)
-		l.frequencies.AddFrontOrAfter(linkedlist.NewNode(currentFreq.Key+1, newList), currentFreq)
+	hasNextFreq := currentFreq != l.frequencies.Last() && nextFreq.Key == currentFreq.Key+1
+
+	// The node is alone in its bucket and there is no freq+1 bucket to join:
+	// rekey the bucket in place instead of allocating a new one and dropping this one.
+	if !hasNextFreq && currentFreq.Value.First() == value && currentFreq.Value.Last() == value {
+		currentFreq.Key++
+		l.debugValidate()
+		return value
+	}
+
+	if !hasNextFreq {
+		bucket := l.newBucket(currentFreq.Key + 1)
+		bucket.Value.MoveToFront(value)
+		l.frequencies.InsertAfter(bucket, currentFreq)
 	} else {
-		nextFreq.Value.AddFrontOrAfter(value)
+		nextFreq.Value.MoveToFront(value)
 	}
 	node.baseNode = currentFreq.Next()
 
 	if currentFreq.Value.IsEmpty() {
-		currentFreq.Untie()
+		l.releaseBucket(currentFreq)
 	}
 
+	l.debugValidate()
 	return value
 }
 
@@ -142,38 +215,314 @@ func (l *cacheImpl[K, V]) GetKeyFrequency(key K) (int, error) {
 // before inserting a new item. For this problem, when there is a tie
 // (i.e., two or more keys with the same frequencies), the least recently used key would be invalidated.
 //
-// O(1)
+// O(1), except for inserting a key whose frequency is remembered from LoadFrequencies or SoftDelete:
+// finding the bucket of that frequency costs O(number of distinct frequencies).
 func (l *cacheImpl[K, V]) Put(key K, value V) {
+	evicted, replaced := l.put(key, value)
+	if evicted.ok {
+		l.drop(evicted.value, evicted.ref)
+	}
+	if replaced.ok {
+		l.dropReplaced(replaced.value, value, replaced.ref)
+	}
+}
+
+// PutEvict works like Put and additionally returns the entry displaced to make room
+// for the new key. The evicted flag is false if nothing was evicted.
+//
+// Same complexity as Put.
+func (l *cacheImpl[K, V]) PutEvict(key K, value V) (evictedKey K, evictedValue V, evicted bool) {
+	last, replaced := l.put(key, value)
+	if replaced.ok {
+		l.dropReplaced(replaced.value, value, replaced.ref)
+	}
+
+	return last.key, last.value, last.ok
+}
+
+// PutGet works like Put and additionally returns the value previously stored under the key.
+// The replaced flag is false if the key was inserted rather than updated, or the Put was rejected.
+// The key's frequency changes exactly as it would with Put.
+//
+// Same complexity as Put.
+func (l *cacheImpl[K, V]) PutGet(key K, value V) (prev V, replaced bool) {
+	evicted, updated := l.put(key, value)
+	if evicted.ok {
+		l.drop(evicted.value, evicted.ref)
+	}
+
+	return updated.value, updated.ok
+}
+
+// put updates or inserts the key. It returns the entry evicted to make room for a new key
+// and, if an existing key was updated, its previous value together with the handle
+// reference detached from it.
+func (l *cacheImpl[K, V]) put(key K, value V) (evicted, replaced displaced[K, V]) {
+	if l.capacity == 0 {
+		return evicted, replaced
+	}
+
+	if l.admit != nil && !l.admit(key, value) {
+		l.reject(key)
+		return evicted, replaced
+	}
+
 	if cached, exists := l.mp[key]; exists {
+		replaced = displaced[K, V]{key: key, value: cached.node.Value, ok: true}
+		if cached.ref != nil && !sameValue(replaced.value, value) {
+			replaced.ref, cached.ref = cached.ref, nil
+		}
 		cached.node.Value = value
+		l.stats.Replacements++
 		_ = l.hangUpNode(cached)
-		return
+		return evicted, replaced
 	}
 
 	if l.Size() >= l.capacity {
-		l.delLast()
+		evicted = l.delLast()
 	}
 
-	node := linkedlist.NewNode(key, value)
-	if l.frequencies.First().Key == 1 {
-		l.frequencies.First().Value.AddFrontOrAfter(node)
-	} else {
-		newList := linkedlist.NewList[K, V]()
-		newList.AddFrontOrAfter(node)
-		l.frequencies.AddFrontOrAfter(linkedlist.NewNode(1, newList))
+	freq := 1
+	if len(l.history) > 0 || len(l.tombstones) > 0 {
+		freq = l.recall(key)
 	}
-	l.mp[key] = &cacheNode[K, V]{node: node, baseNode: l.frequencies.First()}
+
+	bucket := l.bucketFor(freq)
+	node := bucket.Value.NewNode(key, value)
+	bucket.Value.PushFront(node)
+	cached := l.newEntry(node, bucket)
+	if l.insertion != nil {
+		cached.order = linkedlist.NewNode(key, cached)
+		l.insertion.PushBack(cached.order)
+	}
+	l.mp[key] = cached
+	if l.sampling {
+		cached.slot = len(l.slots)
+		l.slots = append(l.slots, cached)
+	}
+	l.debugValidate()
+
+	return evicted, replaced
 }
 
-// delLast removes the least frequently used item from the cache.
+// reject counts a Put refused by the admission hooks. An existing entry for the key is deleted,
+// since the caller meant to replace its value and keeping the old one would serve stale data.
+func (l *cacheImpl[K, V]) reject(key K) {
+	l.stats.Rejections++
+	if cached, exists := l.mp[key]; exists {
+		gone := l.remove(cached)
+		l.drop(gone.value, gone.ref)
+	}
+}
+
+// delLast evicts the least frequently used item from the cache and returns it.
 // It updates the internal data structures accordingly to maintain the LFU policy.
-func (l *cacheImpl[K, V]) delLast() {
-	node := l.frequencies.First().Value.Last()
-	node.Untie()
-	delete(l.mp, node.Key)
-	if l.frequencies.First().Value.IsEmpty() {
-		l.frequencies.First().Untie()
+func (l *cacheImpl[K, V]) delLast() displaced[K, V] {
+	l.stats.Evictions++
+	return l.remove(l.mp[l.frequencies.First().Value.Last().Key])
+}
+
+// remove unlinks the entry from its frequency bucket, the insertion list and the map,
+// dropping the bucket if it becomes empty. The entry node is freed for reuse,
+// so the removed key and value are returned instead.
+func (l *cacheImpl[K, V]) remove(cached *cacheNode[K, V]) displaced[K, V] {
+	gone := displaced[K, V]{key: cached.node.Key, value: cached.node.Value, ref: cached.ref, ok: true}
+	cached.baseNode.Value.Free(cached.node)
+	if cached.order != nil {
+		cached.order.Untie()
+	}
+	delete(l.mp, gone.key)
+	if l.sampling {
+		l.freeSlot(cached)
+	}
+	if cached.baseNode.Value.IsEmpty() {
+		l.releaseBucket(cached.baseNode)
 	}
+	*cached = cacheNode[K, V]{}
+	l.spareEntry = cached
+	l.debugValidate()
+
+	return gone
+}
+
+// newEntry returns an entry for the node, reusing the one removed last if available.
+// A Put into a full cache removes an entry right before inserting one,
+// so reusing it saves an allocation per Put.
+func (l *cacheImpl[K, V]) newEntry(
+	node *linkedlist.Node[K, V], bucket *linkedlist.Node[int, *linkedlist.List[K, V]],
+) *cacheNode[K, V] {
+	cached := l.spareEntry
+	if cached == nil {
+		return &cacheNode[K, V]{node: node, baseNode: bucket}
+	}
+
+	l.spareEntry = nil
+	cached.node = node
+	cached.baseNode = bucket
+	return cached
+}
+
+// freeSlot removes the entry from slots by moving the last entry into its place.
+func (l *cacheImpl[K, V]) freeSlot(cached *cacheNode[K, V]) {
+	last := len(l.slots) - 1
+	moved := l.slots[last]
+	moved.slot = cached.slot
+	l.slots[cached.slot] = moved
+	l.slots[last] = nil
+	l.slots = l.slots[:last]
+}
+
+// bucketFor returns the bucket of the given frequency, creating it if needed.
+//
+// O(1) for frequency 1, O(number of distinct frequencies) otherwise.
+func (l *cacheImpl[K, V]) bucketFor(freq int) *linkedlist.Node[int, *linkedlist.List[K, V]] {
+	mark := l.frequencies.Front()
+	for mark != nil && mark.Key < freq {
+		mark = mark.Next()
+	}
+	if mark != nil && mark.Key == freq {
+		return mark
+	}
+
+	bucket := l.newBucket(freq)
+	switch mark {
+	case nil:
+		l.frequencies.PushBack(bucket)
+	case l.frequencies.Front():
+		l.frequencies.PushFront(bucket)
+	default:
+		l.frequencies.InsertBefore(bucket, mark)
+	}
+
+	return bucket
+}
+
+// newBucket returns an empty, detached frequency bucket, reusing the spare one if available.
+// A Put followed by a Get of the new key empties the bucket of frequency 1 on every call,
+// so reusing it saves the allocations of the list and its node.
+func (l *cacheImpl[K, V]) newBucket(freq int) *linkedlist.Node[int, *linkedlist.List[K, V]] {
+	bucket := l.spare
+	if bucket == nil {
+		return l.frequencies.NewNode(freq, linkedlist.NewListWithArena(l.nodes))
+	}
+
+	l.spare = nil
+	bucket.Key = freq
+	return bucket
+}
+
+// releaseBucket unties the empty bucket from the frequency list and keeps it for reuse,
+// or returns it to the arena if a spare bucket is kept already.
+func (l *cacheImpl[K, V]) releaseBucket(bucket *linkedlist.Node[int, *linkedlist.List[K, V]]) {
+	if l.spare != nil {
+		l.frequencies.Free(bucket)
+		return
+	}
+
+	bucket.Untie()
+	l.spare = bucket
+}
+
+// CompareAndDelete removes the key only if its current value equals the expected one
+// according to the equal function, and reports whether the entry was removed.
+// It does not affect the key's frequency when the values differ.
+//
+// O(1)
+func (l *cacheImpl[K, V]) CompareAndDelete(key K, expected V, equal func(V, V) bool) bool {
+	cached, exists := l.mp[key]
+	if !exists || !equal(cached.node.Value, expected) {
+		return false
+	}
+
+	gone := l.remove(cached)
+	l.drop(gone.value, gone.ref)
+	return true
+}
+
+// DeleteIf removes all entries matching the predicate in a single pass
+// and returns the number of removed entries.
+//
+// O(size)
+func (l *cacheImpl[K, V]) DeleteIf(pred func(K, V) bool) int {
+	removed := 0
+	for key, cached := range l.mp {
+		if pred(key, cached.node.Value) {
+			gone := l.remove(cached)
+			l.drop(gone.value, gone.ref)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+// Resize changes the cache capacity. When shrinking, the least frequently used entries
+// are evicted until the cache fits, exactly as if they were displaced by Put.
+// Returns ErrInvalidCapacity if the capacity is negative.
+//
+// O(number of evicted entries)
+func (l *cacheImpl[K, V]) Resize(capacity int) error {
+	if capacity < 0 {
+		return fmt.Errorf("%w: %d must not be negative", ErrInvalidCapacity, capacity)
+	}
+
+	l.evict(l.Size() - capacity)
+	l.capacity = capacity
+
+	return nil
+}
+
+// Shed evicts the coldest fraction of the entries, rounded up, in eviction order,
+// e.g. in response to a memory pressure signal. The capacity is left unchanged,
+// so the cache refills as new keys are put. Returns the number of evicted entries,
+// or ErrInvalidFraction if the fraction is not within [0, 1].
+//
+// O(number of evicted entries)
+func (l *cacheImpl[K, V]) Shed(fraction float64) (int, error) {
+	if !(fraction >= 0 && fraction <= 1) {
+		return 0, fmt.Errorf("%w: %v must be within [0, 1]", ErrInvalidFraction, fraction)
+	}
+
+	n := int(math.Ceil(fraction * float64(l.Size())))
+	l.evict(n)
+
+	return n, nil
+}
+
+// evict removes up to n least frequently used entries, dropping their values.
+func (l *cacheImpl[K, V]) evict(n int) {
+	for ; n > 0 && l.Size() > 0; n-- {
+		evicted := l.delLast()
+		l.drop(evicted.value, evicted.ref)
+	}
+}
+
+// Clear removes all entries from the cache, dropping their values. It also forgets the
+// frequencies remembered by LoadFrequencies and SoftDelete, so re-inserted keys start at 1.
+// Capacity and statistics are preserved.
+//
+// O(size + number of remembered frequencies)
+func (l *cacheImpl[K, V]) Clear() {
+	for _, cached := range l.mp {
+		l.drop(cached.node.Value, cached.ref)
+		cached.baseNode.Value.Free(cached.node)
+	}
+
+	for bucket := l.frequencies.Front(); bucket != nil; bucket = l.frequencies.Front() {
+		l.releaseBucket(bucket)
+	}
+	if l.insertion != nil {
+		l.insertion.Clear()
+	}
+	clear(l.mp)
+	clear(l.slots)
+	l.slots = l.slots[:0]
+	clear(l.history)
+	clear(l.tombstones)
+	if l.graveyard != nil {
+		l.graveyard.Clear()
+	}
+	l.debugValidate()
 }
 
 // Size returns the cache size using the map size
@@ -196,16 +545,74 @@ func (l *cacheImpl[K, V]) Capacity() int {
 // O(capacity)
 func (l *cacheImpl[K, V]) All() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		end := l.frequencies.End()
-		start := l.frequencies.End().Prev()
-		for itList := start; !itList.Equals(end); itList = itList.Prev() {
-			valBegin := itList.Value().Value.Begin()
-			valEnd := itList.Value().Value.End()
-			for valNode := valBegin; !valNode.Equals(valEnd); valNode = valNode.Next() {
-				if !yield(valNode.Value().Key, valNode.Value().Value) {
+		for _, bucket := range l.frequencies.Backward() {
+			for k, v := range bucket.All() {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// AllCtx works like All but stops yielding once the context is cancelled,
+// so dumps of large caches can be aborted.
+//
+// O(capacity)
+func (l *cacheImpl[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range l.All() {
+			if ctx.Err() != nil || !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// EntrySeq returns the iterator over entries with their frequencies,
+// in the same order as All.
+//
+// O(capacity)
+func (l *cacheImpl[K, V]) EntrySeq() iter.Seq[Entry[K, V]] {
+	return func(yield func(Entry[K, V]) bool) {
+		for freq, bucket := range l.frequencies.Backward() {
+			for k, v := range bucket.All() {
+				if !yield(Entry[K, V]{Key: k, Value: v, Frequency: freq}) {
 					return
 				}
 			}
 		}
 	}
 }
+
+// AllByInsertion returns the iterator in insertion order, oldest entry first,
+// regardless of frequencies. Updating the value of an existing key does not change its position.
+// Insertion order is only tracked with WithInsertionOrder; otherwise the iterator yields nothing.
+//
+// O(capacity)
+func (l *cacheImpl[K, V]) AllByInsertion() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if l.insertion == nil {
+			return
+		}
+		for k, cached := range l.insertion.All() {
+			if !yield(k, cached.node.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns the iterator over the entries matching the predicate,
+// in the same order as All.
+//
+// O(capacity)
+func (l *cacheImpl[K, V]) Filter(pred func(K, V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range l.All() {
+			if pred(k, v) && !yield(k, v) {
+				return
+			}
+		}
+	}
+}
@@ -1,13 +1,22 @@
 package lfu
 
 import (
+	"context"
+	"io"
 	"iter"
+	"math"
 	"math/rand/v2"
 	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/stretchr/testify/require"
+
+	"lfucache/internal/linkedlist"
 )
 
 // must compile
@@ -518,6 +527,1147 @@ func TestAllIterator(t *testing.T) {
 	require.Equal(t, []int{50, 40, 30, 20, 10}, values)
 }
 
+func TestAllByInsertion(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(WithCapacity[int, int](3), WithInsertionOrder[int, int]())
+
+	cache.Put(1, 10)
+	cache.Put(2, 20)
+	cache.Put(3, 30)
+
+	_, _ = cache.Get(3)
+	_, _ = cache.Get(3)
+	cache.Put(2, 200)
+	cache.Put(4, 40)
+
+	keys, values := collect(cache.AllByInsertion())
+	require.Equal(t, []int{2, 3, 4}, keys)
+	require.Equal(t, []int{200, 30, 40}, values)
+}
+
+func TestAllByInsertionUntracked(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](3)
+	cache.Put(1, 10)
+	cache.Put(2, 20)
+
+	keys, _ := collect(cache.AllByInsertion())
+	require.Empty(t, keys)
+	require.NoError(t, cache.Validate())
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](5)
+
+	for i := 1; i <= 5; i++ {
+		cache.Put(i, i*10)
+	}
+	_, _ = cache.Get(2)
+
+	keys, values := collect(cache.Filter(func(k int, _ int) bool {
+		return k%2 == 0
+	}))
+	require.Equal(t, []int{2, 4}, keys)
+	require.Equal(t, []int{20, 40}, values)
+}
+
+func TestDeleteIf(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(WithCapacity[int, int](5), WithInsertionOrder[int, int]())
+
+	for i := 1; i <= 5; i++ {
+		cache.Put(i, i*10)
+	}
+	_, _ = cache.Get(4)
+
+	removed := cache.DeleteIf(func(k int, _ int) bool {
+		return k%2 == 0
+	})
+	require.Equal(t, 2, removed)
+	require.Equal(t, 3, cache.Size())
+
+	_, err := cache.Get(4)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	keys, _ := collect(cache.All())
+	require.Equal(t, []int{5, 3, 1}, keys)
+
+	keys, _ = collect(cache.AllByInsertion())
+	require.Equal(t, []int{1, 3, 5}, keys)
+
+	cache.Put(6, 60)
+	cache.Put(7, 70)
+	cache.Put(8, 80)
+	require.Equal(t, 5, cache.Size())
+}
+
+func TestCompareAndDelete(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, string](2)
+	equal := func(a, b string) bool { return a == b }
+
+	cache.Put(1, "one")
+
+	require.False(t, cache.CompareAndDelete(1, "stale", equal))
+	require.False(t, cache.CompareAndDelete(2, "one", equal))
+
+	freq, err := cache.GetKeyFrequency(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, freq)
+
+	require.True(t, cache.CompareAndDelete(1, "one", equal))
+	require.Equal(t, 0, cache.Size())
+
+	_, err = cache.Get(1)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestPutEvict(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, string](2)
+
+	_, _, evicted := cache.PutEvict(1, "one")
+	require.False(t, evicted)
+
+	_, _, evicted = cache.PutEvict(2, "two")
+	require.False(t, evicted)
+
+	_, _ = cache.Get(1)
+
+	_, _, evicted = cache.PutEvict(1, "first")
+	require.False(t, evicted)
+
+	key, value, evicted := cache.PutEvict(3, "three")
+	require.True(t, evicted)
+	require.Equal(t, 2, key)
+	require.Equal(t, "two", value)
+}
+
+func TestPutReusesEvictedNodes(t *testing.T) {
+	cache := New[int, int](2)
+	cache.Put(1, 10)
+	cache.Put(2, 20)
+	_, _ = cache.Get(2)
+
+	// Each Put evicts the previous key of frequency 1, whose node the new key then reuses.
+	for i, want := 3, 1; i < 10; i, want = i+1, i {
+		key, value, evicted := cache.PutEvict(i, i*10)
+		require.True(t, evicted)
+		require.Equal(t, want, key)
+		require.Equal(t, want*10, value)
+	}
+	require.NoError(t, cache.Validate())
+
+	i := 10
+	allocs := testing.AllocsPerRun(100, func() {
+		cache.Put(i, i)
+		i++
+	})
+	require.Zero(t, allocs)
+}
+
+func TestPutGet(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, string](2)
+
+	prev, replaced := cache.PutGet(1, "one")
+	require.False(t, replaced)
+	require.Empty(t, prev)
+
+	prev, replaced = cache.PutGet(1, "first")
+	require.True(t, replaced)
+	require.Equal(t, "one", prev)
+
+	freq, err := cache.GetKeyFrequency(1)
+	require.NoError(t, err)
+	require.Equal(t, 2, freq)
+}
+
+type testCloser struct {
+	closed int
+}
+
+func (c *testCloser) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestCloseOnEvict(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(WithCapacity[int, *testCloser](2), WithCloseOnEvict[int, *testCloser]())
+	first, second, third, replacement := &testCloser{}, &testCloser{}, &testCloser{}, &testCloser{}
+
+	cache.Put(1, first)
+	cache.Put(2, second)
+	cache.Put(2, second)
+	require.Equal(t, 0, second.closed)
+
+	cache.Put(3, third)
+	require.Equal(t, 1, first.closed)
+
+	cache.Put(3, replacement)
+	require.Equal(t, 1, third.closed)
+
+	_, value, evicted := cache.PutEvict(4, &testCloser{})
+	require.True(t, evicted)
+	require.Same(t, second, value)
+	require.Equal(t, 0, second.closed)
+
+	cache.DeleteIf(func(int, *testCloser) bool { return true })
+	require.Equal(t, 1, replacement.closed)
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+func TestAsyncCloseOnEvict(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(WithCapacity[int, io.Closer](1), WithAsyncCloseOnEvict[int, io.Closer]())
+
+	var closed sync.WaitGroup
+	closed.Add(asyncCloseQueue + 2)
+	counted := closerFunc(func() error {
+		closed.Done()
+		return nil
+	})
+
+	started, release := make(chan struct{}), make(chan struct{})
+	cache.Put(0, closerFunc(func() error {
+		close(started)
+		<-release
+		return counted()
+	}))
+	cache.Put(1, counted)
+	<-started
+
+	// The background goroutine is stuck, so evicted values queue up until the backlog is full.
+	for key := 2; key <= asyncCloseQueue; key++ {
+		cache.Put(key, counted)
+	}
+	inline := make(chan struct{}, 1)
+	cache.Put(asyncCloseQueue+1, closerFunc(func() error {
+		inline <- struct{}{}
+		return counted()
+	}))
+	require.Empty(t, inline)
+
+	cache.Put(asyncCloseQueue+2, counted)
+	require.Len(t, inline, 1)
+
+	close(release)
+	closed.Wait()
+}
+
+func TestSameValue(t *testing.T) {
+	t.Parallel()
+
+	type holder struct{ value any }
+	shared := &testCloser{}
+
+	require.True(t, sameValue(shared, shared))
+	require.False(t, sameValue(shared, &testCloser{}))
+	require.False(t, sameValue([]int{1}, []int{1}))
+	require.True(t, sameValue(holder{1}, holder{1}))
+	require.False(t, sameValue(holder{[]int{1}}, holder{[]int{1}}))
+
+	cache := NewWithOptions(WithCapacity[int, holder](1), WithCloseOnEvict[int, holder]())
+	cache.Put(1, holder{[]int{1}})
+	cache.Put(1, holder{[]int{2}})
+	value, err := cache.Get(1)
+	require.NoError(t, err)
+	require.Equal(t, holder{[]int{2}}, value)
+}
+
+func TestHandleDefersClose(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(WithCapacity[int, *testCloser](1), WithCloseOnEvict[int, *testCloser]())
+	first, second := &testCloser{}, &testCloser{}
+
+	cache.Put(1, first)
+
+	handle, err := cache.Acquire(1)
+	require.NoError(t, err)
+	other, err := cache.Acquire(1)
+	require.NoError(t, err)
+
+	freq, err := cache.GetKeyFrequency(1)
+	require.NoError(t, err)
+	require.Equal(t, 3, freq)
+
+	cache.Put(2, second)
+	require.Equal(t, 0, first.closed)
+	require.Same(t, first, handle.Value())
+
+	handle.Release()
+	handle.Release()
+	require.Equal(t, 0, first.closed)
+
+	other.Release()
+	require.Equal(t, 1, first.closed)
+
+	_, err = cache.Acquire(1)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestHandleSurvivesSameValuePut(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(WithCapacity[int, *testCloser](1), WithCloseOnEvict[int, *testCloser]())
+	value := &testCloser{}
+
+	cache.Put(1, value)
+	handle, err := cache.Acquire(1)
+	require.NoError(t, err)
+
+	cache.Put(1, value)
+	cache.Put(2, &testCloser{})
+	require.Equal(t, 0, value.closed)
+
+	handle.Release()
+	require.Equal(t, 1, value.closed)
+}
+
+func TestAdmission(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(
+		WithCapacity[string, string](2),
+		WithAdmission(func(_ string, v string) bool { return len(v) <= 3 }),
+	)
+
+	cache.Put("a", "one")
+	cache.Put("b", "three")
+
+	value, err := cache.Get("a")
+	require.NoError(t, err)
+	require.Equal(t, "one", value)
+
+	_, err = cache.Get("b")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	cache.Put("a", "first")
+	_, err = cache.Get("a")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.Zero(t, cache.Size())
+	require.NoError(t, cache.Validate())
+
+	require.Equal(t, Stats{Hits: 1, Misses: 2, Rejections: 2}, cache.Stats())
+}
+
+func TestStatsEvictions(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](1)
+
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(2, 3)
+
+	require.Equal(t, 1, cache.Stats().Evictions)
+	require.Equal(t, 1, cache.Stats().Replacements)
+}
+
+func TestMaxKeyAndValueSize(t *testing.T) {
+	t.Parallel()
+
+	size := func(s string) int { return len(s) }
+	cache := NewWithOptions(
+		WithMaxKeySize[string, string](3, size),
+		WithMaxValueSize[string](5, size),
+	)
+
+	cache.Put("key", "value")
+	cache.Put("long key", "value")
+	cache.Put("k", "long value")
+
+	require.Equal(t, 1, cache.Size())
+	require.Equal(t, 2, cache.Stats().Rejections)
+}
+
+func TestNewWithError(t *testing.T) {
+	t.Parallel()
+
+	cache, err := NewWithError(WithCapacity[int, int](2))
+	require.NoError(t, err)
+	require.Equal(t, 2, cache.Capacity())
+
+	_, err = NewWithError(
+		WithCapacity[int, int](-1),
+		WithMaxValueSize[int, int](-5, func(int) int { return 0 }),
+		WithAdmission[int, int](nil),
+	)
+	require.ErrorIs(t, err, ErrInvalidCapacity)
+	require.ErrorIs(t, err, ErrInvalidOption)
+	require.ErrorContains(t, err, "WithMaxValueSize")
+	require.ErrorContains(t, err, "WithAdmission")
+
+	require.Panics(t, func() {
+		NewWithOptions(WithCapacity[int, int](-1))
+	})
+}
+
+func TestZeroCapacity(t *testing.T) {
+	t.Parallel()
+
+	for _, cache := range []*cacheImpl[int, int]{New[int, int](0), NewWithOptions(WithNop[int, int]())} {
+		cache.Put(1, 10)
+		cache.Put(1, 20)
+		require.Equal(t, 0, cache.Size())
+		require.Equal(t, 0, cache.Capacity())
+
+		_, err := cache.Get(1)
+		require.ErrorIs(t, err, ErrKeyNotFound)
+
+		_, _, evicted := cache.PutEvict(2, 20)
+		require.False(t, evicted)
+
+		keys, _ := collect(cache.All())
+		require.Empty(t, keys)
+		require.Equal(t, Stats{Misses: 1}, cache.Stats())
+	}
+}
+
+func TestEntrySeq(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, string](3)
+
+	cache.Put(1, "one")
+	cache.Put(2, "two")
+	_, _ = cache.Get(1)
+
+	entries := slices.Collect(cache.EntrySeq())
+	require.Equal(t, []Entry[int, string]{
+		{Key: 1, Value: "one", Frequency: 2},
+		{Key: 2, Value: "two", Frequency: 1},
+	}, entries)
+}
+
+func TestSoleBucketMemberPromotion(t *testing.T) {
+	cache := New[int, int](3)
+
+	cache.Put(1, 10)
+	cache.Put(2, 20)
+	for range 3 {
+		_, _ = cache.Get(1)
+	}
+	_, _ = cache.Get(2)
+	_, _ = cache.Get(2)
+	_, _ = cache.Get(2)
+
+	freq, err := cache.GetKeyFrequency(1)
+	require.NoError(t, err)
+	require.Equal(t, 4, freq)
+
+	freq, err = cache.GetKeyFrequency(2)
+	require.NoError(t, err)
+	require.Equal(t, 4, freq)
+
+	keys, _ := collect(cache.All())
+	require.Equal(t, []int{2, 1}, keys)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = cache.Get(2)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestResize(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](5)
+
+	for i := 1; i <= 5; i++ {
+		cache.Put(i, i)
+		for range i {
+			_, _ = cache.Get(i)
+		}
+	}
+
+	require.NoError(t, cache.Resize(2))
+	require.Equal(t, 2, cache.Capacity())
+	require.Equal(t, 2, cache.Size())
+	require.Equal(t, 3, cache.Stats().Evictions)
+
+	keys, _ := collect(cache.All())
+	require.Equal(t, []int{5, 4}, keys)
+
+	require.NoError(t, cache.Resize(3))
+	cache.Put(6, 6)
+	require.Equal(t, 3, cache.Size())
+
+	require.ErrorIs(t, cache.Resize(-1), ErrInvalidCapacity)
+	require.Equal(t, 3, cache.Capacity())
+}
+
+func TestClear(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(
+		WithCapacity[int, *testCloser](2),
+		WithCloseOnEvict[int, *testCloser](),
+		WithInsertionOrder[int, *testCloser](),
+	)
+	value := &testCloser{}
+
+	cache.Put(1, value)
+	cache.Put(2, &testCloser{})
+	_, _ = cache.Get(1)
+
+	cache.Clear()
+	require.Equal(t, 0, cache.Size())
+	require.Equal(t, 1, value.closed)
+
+	keys, _ := collect(cache.All())
+	require.Empty(t, keys)
+
+	cache.Put(3, &testCloser{})
+	keys, _ = collect(cache.AllByInsertion())
+	require.Equal(t, []int{3}, keys)
+
+	_, _ = cache.Get(3)
+	require.True(t, cache.SoftDelete(3))
+	cache.LoadFrequencies(map[int]int{4: 5})
+	cache.Clear()
+	require.Empty(t, cache.SnapshotFrequencies())
+	require.NoError(t, cache.Validate())
+
+	cache.Put(3, &testCloser{})
+	cache.Put(4, &testCloser{})
+	require.Equal(t, map[int]int{3: 1, 4: 1}, cache.GetKeyFrequencies(3, 4))
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](10)
+	for i := 0; i < 100; i++ {
+		cache.Put(rand.N(20), i)
+		_, _ = cache.Get(rand.N(20))
+	}
+	require.NoError(t, cache.Validate())
+
+	for _, cached := range cache.mp {
+		cached.node.Untie()
+		break
+	}
+	require.ErrorIs(t, cache.Validate(), ErrCorrupted)
+}
+
+func TestValidateInvariants(t *testing.T) {
+	t.Parallel()
+
+	corruptions := map[string]func(cache *cacheImpl[int, int]){
+		"bucket order": func(cache *cacheImpl[int, int]) {
+			cache.mp[1].baseNode.Key = 1
+		},
+		"empty bucket": func(cache *cacheImpl[int, int]) {
+			cache.frequencies.PushBack(linkedlist.NewNode(10, linkedlist.NewList[int, int]()))
+		},
+		"size totals": func(cache *cacheImpl[int, int]) {
+			delete(cache.mp, 2)
+		},
+		"capacity": func(cache *cacheImpl[int, int]) {
+			cache.capacity = 1
+		},
+		"sample index": func(cache *cacheImpl[int, int]) {
+			cache.slots[0], cache.slots[1] = cache.slots[1], cache.slots[0]
+		},
+		"insertion list": func(cache *cacheImpl[int, int]) {
+			cache.mp[1].order.Untie()
+		},
+	}
+
+	for name, corrupt := range corruptions {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := NewWithOptions(
+				WithCapacity[int, int](3),
+				WithInsertionOrder[int, int](),
+				WithSampling[int, int](),
+			)
+			cache.Put(1, 1)
+			_, _ = cache.Get(1)
+			_, _ = cache.Get(1)
+			cache.Put(2, 2)
+			_, _ = cache.Get(2)
+			require.NoError(t, cache.Validate())
+
+			corrupt(cache)
+			require.ErrorIs(t, cache.Validate(), ErrCorrupted)
+		})
+	}
+}
+
+func TestFrequencyHistogram(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](5)
+	require.Empty(t, cache.FrequencyHistogram())
+
+	for i := range 4 {
+		cache.Put(i, i)
+	}
+	_, _ = cache.Get(0)
+	_, _ = cache.Get(0)
+	_, _ = cache.Get(1)
+
+	require.Equal(t, []FrequencyCount{
+		{Frequency: 1, Count: 2},
+		{Frequency: 2, Count: 1},
+		{Frequency: 3, Count: 1},
+	}, cache.FrequencyHistogram())
+}
+
+func TestTopKAndBottomK(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, string](5)
+	cache.Put(1, "one")
+	cache.Put(2, "two")
+	cache.Put(3, "three")
+	_, _ = cache.Get(1)
+	_, _ = cache.Get(1)
+	_, _ = cache.Get(3)
+
+	require.Equal(t, []Entry[int, string]{
+		{Key: 1, Value: "one", Frequency: 3},
+		{Key: 3, Value: "three", Frequency: 2},
+	}, cache.TopK(2))
+	require.Equal(t, []Entry[int, string]{
+		{Key: 2, Value: "two", Frequency: 1},
+		{Key: 3, Value: "three", Frequency: 2},
+	}, cache.BottomK(2))
+
+	require.Len(t, cache.TopK(10), 3)
+	require.Len(t, cache.BottomK(10), 3)
+	require.Empty(t, cache.TopK(0))
+	require.Empty(t, cache.BottomK(-1))
+}
+
+func TestGetKeyFrequencies(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string, int](3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	_, _ = cache.Get("a")
+
+	require.Equal(t, map[string]int{"a": 2, "b": 1}, cache.GetKeyFrequencies("a", "b", "missing"))
+	require.Empty(t, cache.GetKeyFrequencies())
+
+	freq, err := cache.GetKeyFrequency("a")
+	require.NoError(t, err)
+	require.Equal(t, 2, freq)
+}
+
+func TestFrequencySaturates(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](3)
+	cache.Put(1, 1)
+	_, _ = cache.Get(1)
+	cache.Put(2, 2)
+	cache.mp[1].baseNode.Key = MaxFrequency - 1
+	cache.mp[2].baseNode.Key = MaxFrequency - 2
+
+	for range 3 {
+		_, _ = cache.Get(1)
+		_, _ = cache.Get(2)
+	}
+	require.NoError(t, cache.Validate())
+	require.Equal(t, map[int]int{1: MaxFrequency, 2: MaxFrequency}, cache.GetKeyFrequencies(1, 2))
+
+	_, _ = cache.Get(1)
+	keys, _ := collect(cache.All())
+	require.Equal(t, []int{1, 2}, keys)
+
+	_, _ = cache.Get(2)
+	keys, _ = collect(cache.All())
+	require.Equal(t, []int{2, 1}, keys)
+}
+
+func TestStatsDeltaAndReset(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](1)
+	cache.Put(1, 1)
+	_, _ = cache.Get(1)
+	prev := cache.Stats()
+
+	_, _ = cache.Get(1)
+	_, _ = cache.Get(2)
+	cache.Put(2, 2)
+	require.Equal(t, Stats{Hits: 1, Misses: 1, Evictions: 1}, cache.Stats().Delta(prev))
+
+	cache.ResetStats()
+	require.Equal(t, Stats{}, cache.Stats())
+	require.Equal(t, 1, cache.Size())
+}
+
+func TestShed(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](10)
+	for i := range 10 {
+		cache.Put(i, i)
+		for range i {
+			_, _ = cache.Get(i)
+		}
+	}
+
+	shed, err := cache.Shed(0.25)
+	require.NoError(t, err)
+	require.Equal(t, 3, shed)
+	require.Equal(t, 7, cache.Size())
+	require.Equal(t, 10, cache.Capacity())
+	require.Equal(t, 3, cache.Stats().Evictions)
+	require.Empty(t, cache.GetKeyFrequencies(0, 1, 2))
+
+	shed, err = cache.Shed(0)
+	require.NoError(t, err)
+	require.Zero(t, shed)
+
+	for _, fraction := range []float64{-0.1, 1.5, math.NaN()} {
+		_, err = cache.Shed(fraction)
+		require.ErrorIs(t, err, ErrInvalidFraction)
+	}
+
+	shed, err = cache.Shed(1)
+	require.NoError(t, err)
+	require.Equal(t, 7, shed)
+	require.Zero(t, cache.Size())
+}
+
+func TestNop(t *testing.T) {
+	cache := NewNop[string, int]()
+
+	cache.Put("a", 1)
+	_, err := cache.Get("a")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	_, err = cache.GetKeyFrequency("a")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.Zero(t, cache.Size())
+	require.Zero(t, cache.Capacity())
+	keys, _ := collect(cache.All())
+	require.Empty(t, keys)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		nop := NewNop[string, int]()
+		nop.Put("a", 1)
+		_, _ = nop.Get("a")
+	})
+	require.Zero(t, allocs)
+}
+
+func TestCache2(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache2[string, int, string](4)
+	cache.Put("tenant-a", 1, "a1")
+	cache.Put("tenant-a", 2, "a2")
+	cache.Put("tenant-b", 1, "b1")
+	cache.Put("tenant-a", 1, "a1 updated")
+
+	value, err := cache.Get("tenant-a", 1)
+	require.NoError(t, err)
+	require.Equal(t, "a1 updated", value)
+
+	freq, err := cache.GetKeyFrequency("tenant-a", 1)
+	require.NoError(t, err)
+	require.Equal(t, 3, freq)
+
+	require.Equal(t, 2, cache.InvalidateAll("tenant-a"))
+	require.Zero(t, cache.InvalidateAll("tenant-a"))
+	require.Equal(t, 1, cache.Size())
+	_, err = cache.Get("tenant-a", 2)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	require.True(t, cache.Delete("tenant-b", 1))
+	require.False(t, cache.Delete("tenant-b", 1))
+	require.Empty(t, cache.index)
+	require.NoError(t, cache.cache.Validate())
+}
+
+func TestCache2EvictionKeepsIndex(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache2[string, int, int](2)
+	cache.Put("a", 1, 1)
+	_, _ = cache.Get("a", 1)
+	cache.Put("b", 1, 1)
+	cache.Put("a", 2, 2)
+
+	_, err := cache.Get("b", 1)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.NotContains(t, cache.index, "b")
+	require.Equal(t, 2, cache.InvalidateAll("a"))
+	require.Zero(t, cache.Size())
+}
+
+func TestFrequencyHistory(t *testing.T) {
+	t.Parallel()
+
+	before := New[string, int](3)
+	before.Put("hot", 1)
+	before.Put("warm", 2)
+	for range 4 {
+		_, _ = before.Get("hot")
+	}
+	_, _ = before.Get("warm")
+
+	snapshot := before.SnapshotFrequencies()
+	require.Equal(t, map[string]int{"hot": 5, "warm": 2}, snapshot)
+
+	after := New[string, int](3)
+	after.Put("warm", 2)
+	after.LoadFrequencies(snapshot)
+	after.LoadFrequencies(map[string]int{"cold": 1})
+	require.Equal(t, map[string]int{"hot": 5, "warm": 1}, after.SnapshotFrequencies())
+
+	after.Put("hot", 1)
+	after.Put("new", 3)
+	require.Equal(t, map[string]int{"hot": 5, "warm": 1, "new": 1}, after.GetKeyFrequencies("hot", "warm", "new"))
+	require.Empty(t, after.history)
+	require.NoError(t, after.Validate())
+
+	after.Put("cold", 4)
+	keys, _ := collect(after.All())
+	require.Equal(t, []string{"hot", "cold", "new"}, keys)
+}
+
+func TestImportCSV(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string, int](3)
+	input := "a,1,x\nb,2,y\na,3,z\n"
+
+	imported, err := ImportCSV(cache, strings.NewReader(input), 0, 1, identity, strconv.Atoi)
+	require.NoError(t, err)
+	require.Equal(t, 3, imported)
+
+	keys, values := collect(cache.All())
+	require.Equal(t, []string{"a", "b"}, keys)
+	require.Equal(t, []int{3, 2}, values)
+
+	_, err = ImportCSV(cache, strings.NewReader("c,4\nd,x\n"), 0, 1, identity, strconv.Atoi)
+	require.ErrorContains(t, err, "csv line 2: value")
+	require.ErrorIs(t, err, strconv.ErrSyntax)
+
+	_, err = ImportCSV(cache, strings.NewReader("e\n"), 0, 1, identity, strconv.Atoi)
+	require.ErrorContains(t, err, "csv line 1")
+	require.ErrorIs(t, err, ErrInvalidColumn)
+
+	for _, cols := range [][2]int{{-1, 1}, {0, -1}} {
+		imported, err = ImportCSV(cache, strings.NewReader("f,5\n"), cols[0], cols[1], identity, strconv.Atoi)
+		require.ErrorIs(t, err, ErrInvalidColumn)
+		require.Zero(t, imported)
+	}
+	_, err = cache.Get("f")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestImportNDJSON(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string, []int](3)
+	input := `{"key": "a", "value": [1, 2]}
+{"key": "b", "value": [3], "frequency": 7}
+`
+
+	imported, err := ImportNDJSON(cache, strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, 2, imported)
+
+	value, err := cache.Get("b")
+	require.NoError(t, err)
+	require.Equal(t, []int{3}, value)
+
+	imported, err = ImportNDJSON(cache, strings.NewReader(`{"key": "c", "value": [1]}`+"\n{\"key\": 1}"))
+	require.Error(t, err)
+	require.Equal(t, 1, imported)
+}
+
+func TestImportNDJSONRejected(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(
+		WithCapacity[string, int](2),
+		WithAdmission(func(_ string, v int) bool { return v < 100 }),
+	)
+	input := `{"key": "a", "value": 500, "frequency": 9}
+{"key": "b", "value": 5, "frequency": 4}
+`
+	imported, err := ImportNDJSON(cache, strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, 2, imported)
+	require.Empty(t, cache.history)
+
+	cache.Put("a", 1)
+	require.Equal(t, map[string]int{"a": 1, "b": 4}, cache.GetKeyFrequencies("a", "b"))
+
+	empty := New[string, int](0)
+	_, err = ImportNDJSON(empty, strings.NewReader(input))
+	require.NoError(t, err)
+	require.Empty(t, empty.history)
+}
+
+func TestExportCSVAndNDJSON(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string, int](3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	_, _ = cache.Get("a")
+
+	var csvOut strings.Builder
+	exported, err := ExportCSV(&csvOut, cache, func(k string) string { return k }, strconv.Itoa)
+	require.NoError(t, err)
+	require.Equal(t, 2, exported)
+	require.Equal(t, "a,1,2\nb,2,1\n", csvOut.String())
+
+	var ndjsonOut strings.Builder
+	exported, err = ExportNDJSON(&ndjsonOut, cache)
+	require.NoError(t, err)
+	require.Equal(t, 2, exported)
+	require.Equal(t, `{"key":"a","value":1,"frequency":2}
+{"key":"b","value":2,"frequency":1}
+`, ndjsonOut.String())
+
+	freq, err := cache.GetKeyFrequency("a")
+	require.NoError(t, err)
+	require.Equal(t, 2, freq)
+
+	restored := New[string, int](3)
+	imported, err := ImportNDJSON(restored, strings.NewReader(ndjsonOut.String()))
+	require.NoError(t, err)
+	require.Equal(t, 2, imported)
+	require.Equal(t, slices.Collect(cache.EntrySeq()), slices.Collect(restored.EntrySeq()))
+	require.Empty(t, restored.history)
+	require.NoError(t, restored.Validate())
+}
+
+func TestContextCancellation(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](10)
+	for i := range 10 {
+		cache.Put(i, i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	seen := 0
+	for range cache.AllCtx(ctx) {
+		seen++
+		if seen == 3 {
+			cancel()
+		}
+	}
+	require.Equal(t, 3, seen)
+
+	var out strings.Builder
+	exported, err := ExportNDJSONCtx(ctx, &out, cache)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Zero(t, exported)
+	require.Empty(t, out.String())
+
+	exported, err = ExportCSVCtx(ctx, &out, cache, strconv.Itoa, strconv.Itoa)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Zero(t, exported)
+
+	snapshot, err := cache.SnapshotFrequenciesCtx(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, snapshot)
+
+	keys, _ := collect(cache.AllCtx(context.Background()))
+	require.Len(t, keys, 10)
+}
+
+func identity(s string) (string, error) {
+	return s, nil
+}
+
+func TestStringsAndBytes(t *testing.T) {
+	strs := NewStrings(WithCapacity[string, string](2))
+	strs.Put("a", "alpha")
+
+	value, err := strs.GetBytes([]byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, "alpha", value)
+	_, err = strs.GetBytes([]byte("b"))
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.Equal(t, Stats{Hits: 1, Misses: 1}, strs.Stats())
+
+	freq, err := strs.GetKeyFrequency("a")
+	require.NoError(t, err)
+	require.Equal(t, 2, freq)
+
+	payloads := NewBytes(WithCapacity[string, []byte](2))
+	payloads.Put("p", []byte{1, 2, 3})
+
+	key := []byte("p")
+	payload, err := payloads.GetBytes(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, payload)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = payloads.GetBytes(key)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestSoftDelete(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	cache := NewWithOptions(WithCapacity[string, int](2), WithTombstoneGrace[string, int](time.Minute))
+	cache.now = func() time.Time { return now }
+
+	cache.Put("hot", 1)
+	for range 4 {
+		_, _ = cache.Get("hot")
+	}
+	require.False(t, cache.SoftDelete("missing"))
+	require.True(t, cache.SoftDelete("hot"))
+
+	_, err := cache.Get("hot")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.Zero(t, cache.Size())
+	require.Equal(t, map[string]int{"hot": 5}, cache.SnapshotFrequencies())
+	require.NoError(t, cache.Validate())
+
+	now = now.Add(30 * time.Second)
+	cache.Put("hot", 2)
+	freq, err := cache.GetKeyFrequency("hot")
+	require.NoError(t, err)
+	require.Equal(t, 5, freq)
+	require.Empty(t, cache.tombstones)
+
+	require.True(t, cache.SoftDelete("hot"))
+	now = now.Add(time.Minute)
+	cache.Put("hot", 3)
+	freq, err = cache.GetKeyFrequency("hot")
+	require.NoError(t, err)
+	require.Equal(t, 1, freq)
+	require.NoError(t, cache.Validate())
+}
+
+func TestExpire(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string, int](2)
+	cache.Put("a", 1)
+	_, _ = cache.Get("a")
+	_, _ = cache.Get("a")
+
+	require.True(t, cache.Expire("a"))
+	require.False(t, cache.Expire("a"))
+	_, err := cache.Get("a")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	cache.Put("a", 2)
+	freq, err := cache.GetKeyFrequency("a")
+	require.NoError(t, err)
+	require.Equal(t, 3, freq)
+	require.NoError(t, cache.Validate())
+}
+
+func TestSoftDeleteBoundedByCapacity(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](2)
+	for i := range 3 {
+		cache.Put(i, i)
+		_, _ = cache.Get(i)
+		require.True(t, cache.SoftDelete(i))
+	}
+	require.Len(t, cache.tombstones, 2)
+	require.NotContains(t, cache.tombstones, 0)
+	require.NoError(t, cache.Validate())
+
+	_, err := NewWithError(WithTombstoneGrace[int, int](0))
+	require.ErrorIs(t, err, ErrInvalidOption)
+}
+
+func TestSample(t *testing.T) {
+	t.Parallel()
+
+	caches := map[string]func() *cacheImpl[int, int]{
+		"reservoir": func() *cacheImpl[int, int] {
+			return New[int, int](10)
+		},
+		"index": func() *cacheImpl[int, int] {
+			return NewWithOptions(WithCapacity[int, int](10), WithSampling[int, int]())
+		},
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := newCache()
+			require.Empty(t, cache.Sample(3))
+
+			for i := range 10 {
+				cache.Put(i, i*10)
+			}
+			_, _ = cache.Get(7)
+			before := cache.FrequencyHistogram()
+
+			require.Nil(t, cache.Sample(0))
+			require.Len(t, cache.Sample(20), 10)
+
+			seen := make(map[int]int)
+			for range 1000 {
+				sample := cache.Sample(3)
+				require.Len(t, sample, 3)
+
+				keys := make(map[int]struct{})
+				for _, entry := range sample {
+					require.Equal(t, entry.Key*10, entry.Value)
+					keys[entry.Key] = struct{}{}
+					seen[entry.Key]++
+				}
+				require.Len(t, keys, 3)
+			}
+			require.Len(t, seen, 10)
+			for _, count := range seen {
+				require.InDelta(t, 300, count, 100)
+			}
+			require.Equal(t, before, cache.FrequencyHistogram())
+			require.NoError(t, cache.Validate())
+
+			require.Equal(t, 8, cache.DeleteIf(func(key, _ int) bool { return key < 8 }))
+			keys := make([]int, 0, 2)
+			for _, entry := range cache.Sample(5) {
+				keys = append(keys, entry.Key)
+			}
+			require.ElementsMatch(t, []int{8, 9}, keys)
+			require.NoError(t, cache.Validate())
+
+			cache.Clear()
+			require.Empty(t, cache.Sample(5))
+			require.NoError(t, cache.Validate())
+		})
+	}
+}
+
 func collect[K comparable, V any](iterator iter.Seq2[K, V]) ([]K, []V) {
 	keys := make([]K, 0)
 	values := make([]V, 0)
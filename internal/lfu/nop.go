@@ -0,0 +1,43 @@
+package lfu
+
+import "iter"
+
+// nopCache represents a cache that stores nothing. It has no fields,
+// so creating it and calling its methods does not allocate.
+type nopCache[K comparable, V any] struct{}
+
+// NewNop returns a cache where Put is ignored and Get always misses,
+// so caching can be disabled by configuration without nil checks at call sites.
+// Unlike a zero-capacity cache, it allocates nothing at all.
+func NewNop[K comparable, V any]() Cache[K, V] {
+	return nopCache[K, V]{}
+}
+
+// Get always returns ErrKeyNotFound.
+func (nopCache[K, V]) Get(K) (V, error) {
+	var zeroVal V
+	return zeroVal, ErrKeyNotFound
+}
+
+// Put does nothing.
+func (nopCache[K, V]) Put(K, V) {}
+
+// All returns an empty iterator.
+func (nopCache[K, V]) All() iter.Seq2[K, V] {
+	return func(func(K, V) bool) {}
+}
+
+// Size always returns 0.
+func (nopCache[K, V]) Size() int {
+	return 0
+}
+
+// Capacity always returns 0.
+func (nopCache[K, V]) Capacity() int {
+	return 0
+}
+
+// GetKeyFrequency always returns ErrKeyNotFound.
+func (nopCache[K, V]) GetKeyFrequency(K) (int, error) {
+	return 0, ErrKeyNotFound
+}
@@ -0,0 +1,258 @@
+package lfu
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"lfucache/internal/linkedlist"
+)
+
+var (
+	ErrInvalidCapacity = errors.New("invalid capacity")
+	ErrInvalidOption   = errors.New("invalid option")
+)
+
+// Option configures optional cache behavior for NewWithOptions and NewWithError.
+type Option[K comparable, V any] func(*cacheImpl[K, V])
+
+// NewWithOptions initializes the cache with DefaultCapacity and applies the options in order.
+// It panics if the resulting configuration is invalid; use NewWithError when options come
+// from user-provided configuration.
+//
+// Returns:
+//   - A pointer to a new cacheImpl instance.
+func NewWithOptions[K comparable, V any](opts ...Option[K, V]) *cacheImpl[K, V] {
+	cache, err := newWithOptions(opts...)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return cache
+}
+
+// NewWithError works like NewWithOptions but returns a descriptive error
+// instead of panicking when the capacity or an option is invalid.
+// All problems are reported at once, joined with errors.Join.
+func NewWithError[K comparable, V any](opts ...Option[K, V]) (Cache[K, V], error) {
+	cache, err := newWithOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+func newWithOptions[K comparable, V any](opts ...Option[K, V]) (*cacheImpl[K, V], error) {
+	cache := New[K, V]()
+	for _, opt := range opts {
+		opt(cache)
+	}
+
+	if cache.capacity < 0 {
+		cache.invalid(fmt.Errorf("%w: %d must not be negative", ErrInvalidCapacity, cache.capacity))
+	}
+
+	err := cache.configErr
+	cache.configErr = nil
+
+	return cache, err
+}
+
+// invalid records a configuration problem reported by an option.
+func (l *cacheImpl[K, V]) invalid(err error) {
+	l.configErr = errors.Join(l.configErr, err)
+}
+
+// WithCapacity sets the cache capacity. Must not be negative.
+func WithCapacity[K comparable, V any](capacity int) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.capacity = capacity
+	}
+}
+
+// WithNop turns the cache into a no-op cache that stores nothing: Put is ignored in O(1)
+// and Get always misses. It is the same as WithCapacity(0) and lets caching be switched off
+// by configuration without changing call sites.
+func WithNop[K comparable, V any]() Option[K, V] {
+	return WithCapacity[K, V](0)
+}
+
+// WithCloseOnEvict makes the cache close values implementing io.Closer whenever it drops them
+// without handing them back to the caller: on eviction, on replacement by Put and on deletion.
+// Values returned by PutEvict and PutGet are owned by the caller and are not closed.
+// Errors returned by Close are ignored.
+func WithCloseOnEvict[K comparable, V any]() Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.closeOnEvict = true
+	}
+}
+
+// WithAsyncCloseOnEvict works like WithCloseOnEvict but calls Close in a background goroutine,
+// so slow Close implementations do not block cache operations. Values are closed one at a time
+// in the order they were dropped by a single goroutine, which exits whenever it runs out of values.
+// If Close falls too far behind, the cache closes further values itself until the backlog shrinks.
+func WithAsyncCloseOnEvict[K comparable, V any]() Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.closeOnEvict = true
+		l.closer = &asyncCloser{}
+	}
+}
+
+// WithInsertionOrder makes the cache track the order in which keys were inserted,
+// as listed by AllByInsertion. It costs an extra list node per entry, so it is off by default.
+func WithInsertionOrder[K comparable, V any]() Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		if l.insertion == nil {
+			l.insertion = linkedlist.NewList[K, *cacheNode[K, V]]()
+		}
+	}
+}
+
+// WithSampling makes the cache index its entries so that Sample costs O(n) instead of O(size).
+// Keeping the index up to date slows down Put and deletions, so it is off by default.
+func WithSampling[K comparable, V any]() Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.sampling = true
+	}
+}
+
+// WithAdmission adds a hook consulted on every Put before the entry consumes capacity.
+// If it returns false, the Put is counted in Stats().Rejections and the value is not stored.
+// An existing entry for the key is deleted as well, so Get does not keep returning
+// the value the caller meant to replace.
+// Several admission options can be combined; an entry must pass all of them.
+func WithAdmission[K comparable, V any](admit func(K, V) bool) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		if admit == nil {
+			l.invalid(fmt.Errorf("%w: WithAdmission: nil hook", ErrInvalidOption))
+			return
+		}
+		l.addAdmission(admit)
+	}
+}
+
+// WithTombstoneGrace sets how long SoftDelete remembers the frequency of a deleted key.
+// Must be positive; defaults to DefaultTombstoneGrace.
+func WithTombstoneGrace[K comparable, V any](grace time.Duration) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		if grace <= 0 {
+			l.invalid(fmt.Errorf("%w: WithTombstoneGrace: grace %v must be positive", ErrInvalidOption, grace))
+			return
+		}
+		l.tombstoneGrace = grace
+	}
+}
+
+// WithMaxKeySize rejects Puts whose key is larger than maxSize as measured by size,
+// e.g. the length of a string key or its encoded form.
+func WithMaxKeySize[K comparable, V any](maxSize int, size func(K) int) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		if err := validateSizeLimit("WithMaxKeySize", maxSize, size); err != nil {
+			l.invalid(err)
+			return
+		}
+		l.addAdmission(func(key K, _ V) bool {
+			return size(key) <= maxSize
+		})
+	}
+}
+
+// WithMaxValueSize rejects Puts whose value is larger than maxSize as measured by size,
+// so a single oversized value cannot evict the entire working set.
+func WithMaxValueSize[K comparable, V any](maxSize int, size func(V) int) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		if err := validateSizeLimit("WithMaxValueSize", maxSize, size); err != nil {
+			l.invalid(err)
+			return
+		}
+		l.addAdmission(func(_ K, value V) bool {
+			return size(value) <= maxSize
+		})
+	}
+}
+
+func validateSizeLimit[T any](option string, maxSize int, size func(T) int) error {
+	if size == nil {
+		return fmt.Errorf("%w: %s: nil size function", ErrInvalidOption, option)
+	}
+	if maxSize < 0 {
+		return fmt.Errorf("%w: %s: max size %d must not be negative", ErrInvalidOption, option, maxSize)
+	}
+
+	return nil
+}
+
+// addAdmission chains the hook after the already configured ones.
+func (l *cacheImpl[K, V]) addAdmission(admit func(K, V) bool) {
+	prev := l.admit
+	if prev == nil {
+		l.admit = admit
+		return
+	}
+
+	l.admit = func(key K, value V) bool {
+		return prev(key, value) && admit(key, value)
+	}
+}
+
+// drop releases a value the cache no longer references. If handles to the value
+// are still held, closing is deferred until the last one is released.
+func (l *cacheImpl[K, V]) drop(value V, ref *valueRef[V]) {
+	if ref != nil && ref.refs > 0 {
+		ref.dropped = true
+		return
+	}
+
+	l.closeValue(value)
+}
+
+// dropReplaced releases a value overwritten by Put unless the same value was stored again.
+func (l *cacheImpl[K, V]) dropReplaced(prev, value V, ref *valueRef[V]) {
+	if !l.closeOnEvict {
+		return
+	}
+
+	if sameValue(prev, value) {
+		return
+	}
+
+	l.drop(prev, ref)
+}
+
+// sameValue reports whether both values are the same comparable value, e.g. the same pointer.
+// Values that cannot be compared, such as structs holding a slice in an interface field,
+// are reported as different.
+func sameValue[V any](a, b V) (same bool) {
+	if typ := reflect.TypeOf(a); typ == nil || !typ.Comparable() {
+		return false
+	}
+
+	// A comparable type may still hold an incomparable dynamic value, which panics on ==.
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return any(a) == any(b)
+}
+
+// closeValue closes the value if closing is enabled and the value implements io.Closer.
+func (l *cacheImpl[K, V]) closeValue(value V) {
+	if !l.closeOnEvict {
+		return
+	}
+
+	closer, ok := any(value).(io.Closer)
+	if !ok {
+		return
+	}
+
+	if l.closer != nil {
+		l.closer.close(closer)
+	} else {
+		_ = closer.Close()
+	}
+}
@@ -0,0 +1,53 @@
+package lfu
+
+import "math/rand/v2"
+
+// Sample returns up to n entries chosen uniformly at random, in no particular order,
+// e.g. to estimate the average value size without exporting the whole cache.
+// Like GetKeyFrequency, it does not affect frequencies.
+//
+// O(n) with WithSampling, otherwise O(size), but allocates only the n sampled entries
+func (l *cacheImpl[K, V]) Sample(n int) []Entry[K, V] {
+	if n <= 0 {
+		return nil
+	}
+	if !l.sampling {
+		return l.reservoirSample(n)
+	}
+
+	n = min(n, len(l.slots))
+	sample := make([]Entry[K, V], 0, n)
+	// A partial Fisher-Yates shuffle: the first n slots end up holding the sample.
+	for i := range n {
+		j := i + rand.N(len(l.slots)-i)
+		l.swapSlots(i, j)
+
+		cached := l.slots[i]
+		sample = append(sample, Entry[K, V]{Key: cached.node.Key, Value: cached.node.Value, Frequency: cached.baseNode.Key})
+	}
+
+	return sample
+}
+
+// reservoirSample walks the whole map: the i-th entry replaces a sampled one with probability n/(i+1).
+func (l *cacheImpl[K, V]) reservoirSample(n int) []Entry[K, V] {
+	sample := make([]Entry[K, V], 0, min(n, l.Size()))
+	i := 0
+	for key, cached := range l.mp {
+		entry := Entry[K, V]{Key: key, Value: cached.node.Value, Frequency: cached.baseNode.Key}
+		if len(sample) < n {
+			sample = append(sample, entry)
+		} else if j := rand.N(i + 1); j < n {
+			sample[j] = entry
+		}
+		i++
+	}
+
+	return sample
+}
+
+func (l *cacheImpl[K, V]) swapSlots(i, j int) {
+	l.slots[i], l.slots[j] = l.slots[j], l.slots[i]
+	l.slots[i].slot = i
+	l.slots[j].slot = j
+}
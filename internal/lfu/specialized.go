@@ -0,0 +1,60 @@
+package lfu
+
+// Strings represents an LFU cache of string values keyed by strings.
+// Besides the methods of the generic cache, it supports lookups by []byte keys.
+type Strings struct {
+	*cacheImpl[string, string]
+}
+
+// NewStrings initializes the cache like NewWithOptions.
+//
+// Returns:
+//   - A pointer to a new Strings instance.
+func NewStrings(opts ...Option[string, string]) *Strings {
+	return &Strings{NewWithOptions(opts...)}
+}
+
+// GetBytes works like Get but takes the key as a byte slice, e.g. straight from a network buffer.
+// The key is not copied, so the lookup does not allocate.
+//
+// O(1)
+func (s *Strings) GetBytes(key []byte) (string, error) {
+	return getByBytes(s.cacheImpl, key)
+}
+
+// Bytes represents an LFU cache of byte slice values keyed by strings, e.g. network payloads.
+// Besides the methods of the generic cache, it supports lookups by []byte keys.
+// The cache stores the slices it is given without copying them, so callers must not
+// modify a slice after putting it or after getting it from the cache.
+type Bytes struct {
+	*cacheImpl[string, []byte]
+}
+
+// NewBytes initializes the cache like NewWithOptions.
+//
+// Returns:
+//   - A pointer to a new Bytes instance.
+func NewBytes(opts ...Option[string, []byte]) *Bytes {
+	return &Bytes{NewWithOptions(opts...)}
+}
+
+// GetBytes works like Get but takes the key as a byte slice, e.g. straight from a network buffer.
+// The key is not copied, so the lookup does not allocate.
+//
+// O(1)
+func (b *Bytes) GetBytes(key []byte) ([]byte, error) {
+	return getByBytes(b.cacheImpl, key)
+}
+
+func getByBytes[V any](l *cacheImpl[string, V], key []byte) (V, error) {
+	// The compiler does not allocate for a string conversion used only as a map index.
+	cached, exists := l.mp[string(key)]
+	if !exists {
+		l.stats.Misses++
+		var zeroVal V
+		return zeroVal, ErrKeyNotFound
+	}
+
+	l.stats.Hits++
+	return l.hangUpNode(cached).Value, nil
+}
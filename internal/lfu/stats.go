@@ -0,0 +1,37 @@
+package lfu
+
+// Stats represents the cache counters accumulated since the cache was created.
+type Stats struct {
+	Hits         int // Lookups that found the key.
+	Misses       int // Lookups that did not find the key.
+	Evictions    int // Entries removed to make room for new keys.
+	Rejections   int // Puts refused by the admission hook.
+	Replacements int // Puts that overwrote the value of an existing key.
+}
+
+// Stats returns a snapshot of the cache counters.
+//
+// O(1)
+func (l *cacheImpl[K, V]) Stats() Stats {
+	return l.stats
+}
+
+// Delta returns the counters accumulated since the prev snapshot was taken,
+// e.g. to report per-interval metrics.
+// Snapshots taken before ResetStats are not comparable with later ones.
+func (s Stats) Delta(prev Stats) Stats {
+	return Stats{
+		Hits:         s.Hits - prev.Hits,
+		Misses:       s.Misses - prev.Misses,
+		Evictions:    s.Evictions - prev.Evictions,
+		Rejections:   s.Rejections - prev.Rejections,
+		Replacements: s.Replacements - prev.Replacements,
+	}
+}
+
+// ResetStats sets all cache counters to zero.
+//
+// O(1)
+func (l *cacheImpl[K, V]) ResetStats() {
+	l.stats = Stats{}
+}
@@ -0,0 +1,186 @@
+package lfu
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+)
+
+// ImportCSV puts an entry into the cache for every CSV record read from r, e.g. to pre-load
+// an offline-computed hot set. The key and the value are taken from the columns keyCol and
+// valCol (zero-based) and converted with parseKey and parseValue. Records are put in order,
+// so later records win and are the most recently used. A header row is not expected.
+//
+// Returns the number of records put, and the first read or parse error with its line number.
+// Returns ErrInvalidColumn without reading if a column is negative, or once a record
+// does not have the columns.
+func ImportCSV[K comparable, V any](
+	cache Cache[K, V], r io.Reader, keyCol, valCol int,
+	parseKey func(string) (K, error), parseValue func(string) (V, error),
+) (int, error) {
+	if keyCol < 0 || valCol < 0 {
+		return 0, fmt.Errorf("%w: columns %d and %d must not be negative", ErrInvalidColumn, keyCol, valCol)
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	imported := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return imported, nil
+		}
+		if err != nil {
+			return imported, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if keyCol >= len(record) || valCol >= len(record) {
+			return imported, fmt.Errorf("csv line %d: %w: %d fields, want columns %d and %d",
+				line, ErrInvalidColumn, len(record), keyCol, valCol)
+		}
+
+		key, err := parseKey(record[keyCol])
+		if err != nil {
+			return imported, fmt.Errorf("csv line %d: key: %w", line, err)
+		}
+		value, err := parseValue(record[valCol])
+		if err != nil {
+			return imported, fmt.Errorf("csv line %d: value: %w", line, err)
+		}
+
+		cache.Put(key, value)
+		imported++
+	}
+}
+
+// ImportNDJSON puts an entry into the cache for every JSON object read from r,
+// one per line, such as {"key": "a", "value": 1, "frequency": 3}. Keys and values are
+// decoded with encoding/json. For the caches of this package, a key not yet cached starts
+// with its "frequency", so the output of ExportNDJSON round-trips; other caches ignore it.
+// Objects are put in order, so later objects win and are the most recently used.
+//
+// Returns the number of objects put and the first read or decoding error.
+func ImportNDJSON[K comparable, V any](cache Cache[K, V], r io.Reader) (int, error) {
+	decoder := json.NewDecoder(r)
+	putter, _ := cache.(frequencyPutter[K, V])
+
+	imported := 0
+	for {
+		var entry Entry[K, V]
+		err := decoder.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			return imported, nil
+		}
+		if err != nil {
+			return imported, fmt.Errorf("ndjson object %d: %w", imported+1, err)
+		}
+
+		if putter != nil {
+			putter.putWithFrequency(entry.Key, entry.Value, entry.Frequency)
+		} else {
+			cache.Put(entry.Key, entry.Value)
+		}
+		imported++
+	}
+}
+
+// frequencyPutter is implemented by caches that can insert keys with a given frequency.
+type frequencyPutter[K comparable, V any] interface {
+	putWithFrequency(key K, value V, freq int)
+}
+
+// entrySource is implemented by caches that iterate over their entries with frequencies.
+type entrySource[K comparable, V any] interface {
+	EntrySeq() iter.Seq[Entry[K, V]]
+}
+
+// entries returns the entries of the cache in the order of All, using EntrySeq if the cache
+// provides it and looking up the frequency of every key otherwise.
+func entries[K comparable, V any](cache Cache[K, V]) iter.Seq[Entry[K, V]] {
+	if source, ok := cache.(entrySource[K, V]); ok {
+		return source.EntrySeq()
+	}
+
+	return func(yield func(Entry[K, V]) bool) {
+		for key, value := range cache.All() {
+			freq, _ := cache.GetKeyFrequency(key)
+			if !yield(Entry[K, V]{Key: key, Value: value, Frequency: freq}) {
+				return
+			}
+		}
+	}
+}
+
+// ExportCSV writes one record per entry in the order of All: the key and the value converted
+// with formatKey and formatValue, followed by the frequency. The cache is not modified and
+// frequencies are not affected. No header row is written, so the output can be read back
+// with ImportCSV using columns 0 and 1.
+//
+// Returns the number of records written and the first write error.
+func ExportCSV[K comparable, V any](
+	w io.Writer, cache Cache[K, V], formatKey func(K) string, formatValue func(V) string,
+) (int, error) {
+	return ExportCSVCtx(context.Background(), w, cache, formatKey, formatValue)
+}
+
+// ExportCSVCtx works like ExportCSV but stops before the next record once the context
+// is cancelled, returning the context's error. Records written so far are flushed.
+func ExportCSVCtx[K comparable, V any](
+	ctx context.Context, w io.Writer, cache Cache[K, V], formatKey func(K) string, formatValue func(V) string,
+) (int, error) {
+	writer := csv.NewWriter(w)
+
+	exported := 0
+	for entry := range entries(cache) {
+		if err := ctx.Err(); err != nil {
+			writer.Flush()
+			return exported, err
+		}
+
+		record := []string{formatKey(entry.Key), formatValue(entry.Value), strconv.Itoa(entry.Frequency)}
+		if err := writer.Write(record); err != nil {
+			return exported, err
+		}
+		exported++
+	}
+
+	writer.Flush()
+	return exported, writer.Error()
+}
+
+// ExportNDJSON writes one JSON object per line and entry in the order of All, such as
+// {"key": "a", "value": 1, "frequency": 3}, encoding keys and values with encoding/json.
+// The cache is not modified and frequencies are not affected.
+//
+// Returns the number of objects written and the first encoding or write error.
+func ExportNDJSON[K comparable, V any](w io.Writer, cache Cache[K, V]) (int, error) {
+	return ExportNDJSONCtx(context.Background(), w, cache)
+}
+
+// ExportNDJSONCtx works like ExportNDJSON but stops before the next object once the context
+// is cancelled, returning the context's error.
+func ExportNDJSONCtx[K comparable, V any](ctx context.Context, w io.Writer, cache Cache[K, V]) (int, error) {
+	encoder := json.NewEncoder(w)
+
+	exported := 0
+	for entry := range entries(cache) {
+		if err := ctx.Err(); err != nil {
+			return exported, err
+		}
+
+		if err := encoder.Encode(entry); err != nil {
+			return exported, err
+		}
+		exported++
+	}
+
+	return exported, nil
+}
@@ -0,0 +1,105 @@
+package lfu
+
+import (
+	"time"
+
+	"lfucache/internal/linkedlist"
+)
+
+// DefaultTombstoneGrace represents how long SoftDelete remembers the frequency of a deleted key
+// unless configured with WithTombstoneGrace.
+const DefaultTombstoneGrace = time.Minute
+
+// tombstone represents the frequency record of a soft-deleted key.
+type tombstone struct {
+	freq     int
+	deadline time.Time
+}
+
+// SoftDelete removes the key like a regular delete, so Get misses and the value is dropped,
+// but keeps a tombstone with its frequency for the grace period. If the key is put again
+// before the tombstone expires, it resumes its former frequency instead of starting at 1.
+// Returns false if the key is not in the cache.
+//
+// Tombstones are purged lazily on inserts and soft deletes once they expire.
+// At most capacity tombstones are kept; beyond that the oldest ones are purged early.
+//
+// O(1) amortized
+func (l *cacheImpl[K, V]) SoftDelete(key K) bool {
+	cached, exists := l.mp[key]
+	if !exists {
+		return false
+	}
+
+	freq := cached.baseNode.Key
+	gone := l.remove(cached)
+	l.drop(gone.value, gone.ref)
+	l.bury(key, freq)
+
+	return true
+}
+
+// Expire removes the key right away, e.g. when the underlying data changed, but expects it
+// to stay hot: like SoftDelete, it keeps the key's frequency for the grace period, so a quick
+// re-insert regains it. Returns false if the key is not in the cache.
+//
+// O(1) amortized
+func (l *cacheImpl[K, V]) Expire(key K) bool {
+	return l.SoftDelete(key)
+}
+
+// bury records a tombstone for the deleted key.
+func (l *cacheImpl[K, V]) bury(key K, freq int) {
+	if l.graveyard == nil {
+		l.graveyard = linkedlist.NewList[K, tombstone]()
+		l.tombstones = make(map[K]*linkedlist.Node[K, tombstone])
+	}
+
+	now := l.clock()
+	l.purgeTombstones(now)
+
+	grace := l.tombstoneGrace
+	if grace == 0 {
+		grace = DefaultTombstoneGrace
+	}
+	node := linkedlist.NewNode(key, tombstone{freq: freq, deadline: now.Add(grace)})
+	l.graveyard.PushBack(node)
+	l.tombstones[key] = node
+
+	for len(l.tombstones) > l.capacity {
+		l.unbury(l.graveyard.Front())
+	}
+}
+
+// exhume removes the tombstone of the key and returns its frequency, or 0 if there is none.
+func (l *cacheImpl[K, V]) exhume(key K) int {
+	l.purgeTombstones(l.clock())
+
+	node, exists := l.tombstones[key]
+	if !exists {
+		return 0
+	}
+
+	l.unbury(node)
+	return node.Value.freq
+}
+
+// purgeTombstones removes the tombstones that expired by now, oldest first.
+func (l *cacheImpl[K, V]) purgeTombstones(now time.Time) {
+	for oldest := l.graveyard.Front(); oldest != nil && !now.Before(oldest.Value.deadline); oldest = l.graveyard.Front() {
+		l.unbury(oldest)
+	}
+}
+
+func (l *cacheImpl[K, V]) unbury(node *linkedlist.Node[K, tombstone]) {
+	node.Untie()
+	delete(l.tombstones, node.Key)
+}
+
+func (l *cacheImpl[K, V]) clock() time.Time {
+	if l.now == nil {
+		return time.Now()
+	}
+
+	return l.now()
+}
@@ -0,0 +1,110 @@
+package lfu
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrCorrupted = errors.New("cache is corrupted")
+
+// Validate checks the structural invariants of the cache: every list is well-formed,
+// bucket frequencies are positive and strictly increasing, no bucket is empty,
+// every map entry is a member of its frequency bucket and, if tracked, of the insertion list,
+// and the size matches the bucket and insertion list totals without exceeding the capacity.
+// It returns an error wrapping ErrCorrupted describing the first violation found.
+// Building with the lfudebug tag runs it after every mutation and panics on failure.
+//
+// O(size)
+func (l *cacheImpl[K, V]) Validate() error {
+	if err := l.frequencies.Validate(); err != nil {
+		return fmt.Errorf("%w: frequency list: %w", ErrCorrupted, err)
+	}
+	if err := l.validateInsertion(); err != nil {
+		return err
+	}
+	total, prevFreq := 0, 0
+	for freq, bucket := range l.frequencies.All() {
+		if err := bucket.Validate(); err != nil {
+			return fmt.Errorf("%w: bucket %d: %w", ErrCorrupted, freq, err)
+		}
+		switch {
+		case freq <= prevFreq:
+			return fmt.Errorf("%w: bucket %d follows bucket %d", ErrCorrupted, freq, prevFreq)
+		case bucket.IsEmpty():
+			return fmt.Errorf("%w: bucket %d is empty", ErrCorrupted, freq)
+		}
+		prevFreq = freq
+		total += bucket.Len()
+	}
+
+	switch {
+	case total != len(l.mp):
+		return fmt.Errorf("%w: buckets hold %d entries, map holds %d", ErrCorrupted, total, len(l.mp))
+	case l.sampling && len(l.slots) != len(l.mp):
+		return fmt.Errorf("%w: %d entries indexed for sampling, map holds %d", ErrCorrupted, len(l.slots), len(l.mp))
+	case len(l.mp) > l.capacity:
+		return fmt.Errorf("%w: size %d exceeds capacity %d", ErrCorrupted, len(l.mp), l.capacity)
+	}
+
+	if l.graveyard != nil {
+		if err := l.graveyard.Validate(); err != nil {
+			return fmt.Errorf("%w: tombstones: %w", ErrCorrupted, err)
+		}
+		if l.graveyard.Len() != len(l.tombstones) {
+			return fmt.Errorf("%w: %d tombstones listed, %d indexed", ErrCorrupted, l.graveyard.Len(), len(l.tombstones))
+		}
+	}
+	for key := range l.tombstones {
+		if _, exists := l.mp[key]; exists {
+			return fmt.Errorf("%w: key %v is both cached and soft-deleted", ErrCorrupted, key)
+		}
+	}
+
+	for key, cached := range l.mp {
+		switch {
+		case cached.node.Key != key:
+			return fmt.Errorf("%w: key %v is stored under %v", ErrCorrupted, cached.node.Key, key)
+		case !l.frequencies.ContainsNode(cached.baseNode):
+			return fmt.Errorf("%w: bucket of key %v is not in the frequency list", ErrCorrupted, key)
+		case !cached.baseNode.Value.ContainsNode(cached.node):
+			return fmt.Errorf("%w: key %v is not in its bucket", ErrCorrupted, key)
+		case l.sampling && (cached.slot < 0 || cached.slot >= len(l.slots) || l.slots[cached.slot] != cached):
+			return fmt.Errorf("%w: key %v is not indexed for sampling", ErrCorrupted, key)
+		}
+	}
+
+	return nil
+}
+
+// validateInsertion checks the insertion list against the map if insertion order is tracked.
+func (l *cacheImpl[K, V]) validateInsertion() error {
+	if l.insertion == nil {
+		return nil
+	}
+
+	if err := l.insertion.Validate(); err != nil {
+		return fmt.Errorf("%w: insertion list: %w", ErrCorrupted, err)
+	}
+	if l.insertion.Len() != len(l.mp) {
+		return fmt.Errorf("%w: insertion list holds %d entries, map holds %d", ErrCorrupted, l.insertion.Len(), len(l.mp))
+	}
+	for key, cached := range l.mp {
+		if cached.order == nil || !l.insertion.ContainsNode(cached.order) || cached.order.Value != cached {
+			return fmt.Errorf("%w: key %v is not in the insertion list", ErrCorrupted, key)
+		}
+	}
+
+	return nil
+}
+
+// debugValidate panics if the cache is corrupted. It compiles to nothing
+// unless the package is built with the lfudebug tag.
+func (l *cacheImpl[K, V]) debugValidate() {
+	if !debugChecks {
+		return
+	}
+
+	if err := l.Validate(); err != nil {
+		panic(err)
+	}
+}
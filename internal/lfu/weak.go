@@ -0,0 +1,83 @@
+//go:build go1.24
+
+package lfu
+
+import "weak"
+
+// WeakCache represents an LFU cache that holds its values through weak pointers,
+// so the garbage collector may reclaim a value that is not referenced elsewhere
+// even before the LFU policy evicts it. Get treats reclaimed entries as misses.
+// It suits large values that are reasonably cheap to rebuild.
+//
+// A reclaimed entry keeps occupying capacity until it is looked up, evicted or purged.
+type WeakCache[K comparable, V any] struct {
+	cache *cacheImpl[K, weak.Pointer[V]]
+}
+
+// NewWeak initializes the weak-value cache with the given capacity.
+// If no value is provided, it defaults to DefaultCapacity.
+//
+// Returns:
+//   - A pointer to a new WeakCache instance.
+func NewWeak[K comparable, V any](capacity ...int) *WeakCache[K, V] {
+	return &WeakCache[K, V]{cache: New[K, weak.Pointer[V]](capacity...)}
+}
+
+// Get returns the value of the key if the key exists in the cache and its value
+// has not been reclaimed, otherwise, returns ErrKeyNotFound.
+// A reclaimed entry is removed and counted as a miss.
+//
+// O(1)
+func (w *WeakCache[K, V]) Get(key K) (*V, error) {
+	cached, exists := w.cache.mp[key]
+	if exists {
+		if value := cached.node.Value.Value(); value != nil {
+			w.cache.stats.Hits++
+			_ = w.cache.hangUpNode(cached)
+			return value, nil
+		}
+		_ = w.cache.remove(cached)
+	}
+
+	w.cache.stats.Misses++
+	return nil, ErrKeyNotFound
+}
+
+// Put updates the value of the key if present, or inserts the key if not already present,
+// evicting the least frequently used key when the cache is full.
+// The cache does not keep the value alive.
+//
+// O(1)
+func (w *WeakCache[K, V]) Put(key K, value *V) {
+	w.cache.Put(key, weak.Make(value))
+}
+
+// Purge removes the entries whose values have been reclaimed and returns their number.
+//
+// O(size)
+func (w *WeakCache[K, V]) Purge() int {
+	return w.cache.DeleteIf(func(_ K, value weak.Pointer[V]) bool {
+		return value.Value() == nil
+	})
+}
+
+// Size returns the number of entries, including reclaimed ones that have not been removed yet.
+//
+// O(1)
+func (w *WeakCache[K, V]) Size() int {
+	return w.cache.Size()
+}
+
+// Capacity returns the cache capacity.
+//
+// O(1)
+func (w *WeakCache[K, V]) Capacity() int {
+	return w.cache.Capacity()
+}
+
+// Stats returns a snapshot of the cache counters.
+//
+// O(1)
+func (w *WeakCache[K, V]) Stats() Stats {
+	return w.cache.Stats()
+}
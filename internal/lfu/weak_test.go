@@ -0,0 +1,40 @@
+//go:build go1.24
+
+package lfu
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type blob struct {
+	data [1 << 10]byte
+}
+
+func TestWeakCacheReclaimedValueMisses(t *testing.T) {
+	cache := NewWeak[string, blob](3)
+
+	kept := &blob{}
+	cache.Put("kept", kept)
+	cache.Put("dropped", &blob{})
+	cache.Put("purged", &blob{})
+
+	runtime.GC()
+
+	value, err := cache.Get("kept")
+	require.NoError(t, err)
+	require.Same(t, kept, value)
+
+	_, err = cache.Get("dropped")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.Equal(t, Stats{Hits: 1, Misses: 1}, cache.Stats())
+	require.Equal(t, 2, cache.Size())
+
+	require.Equal(t, 1, cache.Purge())
+	require.Equal(t, 1, cache.Size())
+	require.NoError(t, cache.cache.Validate())
+
+	runtime.KeepAlive(kept)
+}
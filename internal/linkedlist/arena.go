@@ -0,0 +1,108 @@
+package linkedlist
+
+// DefaultArenaChunk represents the default number of nodes an Arena allocates at once.
+const DefaultArenaChunk = 64
+
+// Arena allocates nodes in chunks and reuses freed ones, reducing allocator pressure
+// for lists that create and drop many nodes. An arena may be shared by several lists,
+// so nodes can move between them. Like List, it is not safe for concurrent use.
+type Arena[K, V any] struct {
+	chunkSize int
+	chunk     []Node[K, V] // Unused part of the current chunk.
+	free      *Node[K, V]  // Freed nodes, linked through next and marked by prev pointing to themselves.
+}
+
+// NewArena initializes an arena with the specified chunk size.
+// If no chunk size is provided, it defaults to DefaultArenaChunk.
+//
+// Arguments:
+//   - chunkSize: Optional integer specifying how many nodes are allocated at once.
+//     Must be a positive number if provided.
+//
+// Returns:
+//   - A pointer to a new Arena instance.
+func NewArena[K, V any](chunkSize ...int) *Arena[K, V] {
+	resultChunkSize := DefaultArenaChunk
+	if len(chunkSize) > 0 {
+		if chunkSize[0] <= 0 {
+			panic("Chunk size must be positive.")
+		}
+		resultChunkSize = chunkSize[0]
+	}
+
+	return &Arena[K, V]{chunkSize: resultChunkSize}
+}
+
+// NewNode returns a detached node with the specified key and value,
+// reusing a freed node if there is one.
+func (a *Arena[K, V]) NewNode(key K, value V) *Node[K, V] {
+	if node := a.free; node != nil {
+		a.free = node.next
+		node.next = nil
+		node.prev = nil
+		node.Key = key
+		node.Value = value
+		return node
+	}
+
+	if len(a.chunk) == 0 {
+		a.chunk = make([]Node[K, V], a.chunkSize)
+	}
+	node := &a.chunk[0]
+	a.chunk = a.chunk[1:]
+	node.Key = key
+	node.Value = value
+
+	return node
+}
+
+// Free unties the node and makes it available for reuse. The node must not be used
+// after it is freed. Its key and value are cleared so the arena does not retain them.
+// Freeing a node again before NewNode hands it out panics, since a node listed twice
+// would later be handed out twice.
+func (a *Arena[K, V]) Free(node *Node[K, V]) {
+	if node == nil {
+		return
+	}
+	if node.prev == node {
+		panic("Node is already freed.")
+	}
+
+	node.Untie()
+	var zeroKey K
+	var zeroVal V
+	node.Key = zeroKey
+	node.Value = zeroVal
+	node.next = a.free
+	node.prev = node
+	a.free = node
+}
+
+// NewListWithArena creates a new doubly linked list whose NewNode and Free
+// use the given arena.
+func NewListWithArena[K, V any](a *Arena[K, V]) *List[K, V] {
+	l := NewList[K, V]()
+	l.arena = a
+	return l
+}
+
+// NewNode creates a detached node with the specified key and value, taking it from
+// the list's arena if the list has one. The node still has to be inserted explicitly.
+func (l *List[K, V]) NewNode(key K, value V) *Node[K, V] {
+	if l.arena == nil {
+		return NewNode(key, value)
+	}
+
+	return l.arena.NewNode(key, value)
+}
+
+// Free unties the node and returns it to the list's arena, if the list has one.
+// The node must not be used afterwards.
+func (l *List[K, V]) Free(node *Node[K, V]) {
+	if l.arena == nil {
+		node.Untie()
+		return
+	}
+
+	l.arena.Free(node)
+}
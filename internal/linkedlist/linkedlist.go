@@ -1,35 +1,109 @@
 package linkedlist
 
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+var ErrCorrupted = errors.New("linked list is corrupted")
+
 // Node represents a node in the doubly linked list.
 // It stores a key of type K and a value of type V.
 // Each node has pointers to the next and previous nodes in the list.
-type Node[K comparable, V any] struct {
+type Node[K, V any] struct {
 	Key   K           // The key associated with the node.
 	Value V           // The value stored in the node.
 	next  *Node[K, V] // Pointer to the next node in the list.
 	prev  *Node[K, V] // Pointer to the previous node in the list.
+	own   *owner      // Membership token of the list the node belongs to, nil if detached.
 }
 
 // NewNode creates a new node with the specified key and value.
 // It initializes the next and previous pointers with empty nodes.
-func NewNode[K comparable, V any](key K, value V) *Node[K, V] {
+func NewNode[K, V any](key K, value V) *Node[K, V] {
 	return &Node[K, V]{Key: key, Value: value, next: nil, prev: nil}
 }
 
 // List represents a doubly linked list.
 // It uses a sentinel node to simplify boundary conditions.
-type List[K comparable, V any] struct {
+type List[K, V any] struct {
 	sentinel *Node[K, V]
+	own      *owner       // Membership token shared by the nodes of the list.
+	arena    *Arena[K, V] // Node allocator used by NewNode and Free, nil for the heap.
+}
+
+// owner identifies the list a node belongs to and counts its nodes.
+// When a list is spliced into another one, its token is chained to the receiving list's token
+// instead of updating every moved node, so membership is resolved like in a union-find structure.
+type owner struct {
+	len    int    // The number of nodes in the list, excluding the sentinel. Valid for roots only.
+	parent *owner // The token of the list the nodes were spliced into, nil for a root.
+}
+
+// root returns the token of the list currently owning the nodes.
+// It only reads the chain, so it is safe under a read lock.
+func (o *owner) root() *owner {
+	for o.parent != nil {
+		o = o.parent
+	}
+
+	return o
+}
+
+// compress returns the same token as root, halving the chain on the way.
+// It writes to the chain, so only operations modifying a list may call it.
+func (o *owner) compress() *owner {
+	for o.parent != nil {
+		if o.parent.parent != nil {
+			o.parent = o.parent.parent
+		}
+		o = o.parent
+	}
+
+	return o
+}
+
+// header holds the sentinel and the initial membership token in a single allocation.
+type header[K, V any] struct {
+	sentinel Node[K, V]
+	own      owner
 }
 
 // NewList creates and initializes a new doubly linked list.
 // It initializes the sentinel node, setting its next and prev pointers to itself.
 // This makes it easier to manage the head and tail.
-func NewList[K comparable, V any]() *List[K, V] {
-	sentinel := &Node[K, V]{}
-	sentinel.next = sentinel
-	sentinel.prev = sentinel
-	return &List[K, V]{sentinel: sentinel}
+func NewList[K, V any]() *List[K, V] {
+	h := &header[K, V]{}
+	h.sentinel.next = &h.sentinel
+	h.sentinel.prev = &h.sentinel
+	return &List[K, V]{sentinel: &h.sentinel, own: &h.own}
+}
+
+// Pair represents a key-value pair used to build lists in bulk.
+type Pair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// NewListFrom creates a new list holding the pairs yielded by the sequence, in order.
+func NewListFrom[K, V any](seq iter.Seq2[K, V]) *List[K, V] {
+	l := NewList[K, V]()
+	for k, v := range seq {
+		l.PushBack(NewNode(k, v))
+	}
+
+	return l
+}
+
+// NewListFromSlice creates a new list holding the pairs of the slice, in order.
+func NewListFromSlice[K, V any](pairs []Pair[K, V]) *List[K, V] {
+	l := NewList[K, V]()
+	for _, pair := range pairs {
+		l.PushBack(NewNode(pair.Key, pair.Value))
+	}
+
+	return l
 }
 
 // AddFrontOrAfter inserts a new node either at the front of the list
@@ -42,14 +116,87 @@ func (l *List[K, V]) AddFrontOrAfter(newNode *Node[K, V], before ...*Node[K, V])
 		bfr = before[0]
 	}
 
+	l.insertAfter(newNode, bfr)
+}
+
+// PushFront inserts a new node at the front of the list.
+func (l *List[K, V]) PushFront(newNode *Node[K, V]) {
+	l.insertAfter(newNode, l.sentinel)
+}
+
+// PushBack inserts a new node at the back of the list.
+func (l *List[K, V]) PushBack(newNode *Node[K, V]) {
+	l.insertAfter(newNode, l.sentinel.prev)
+}
+
+// InsertBefore inserts a new node right before the mark.
+// The mark must be a node of the list; otherwise the list is left unchanged.
+func (l *List[K, V]) InsertBefore(newNode, mark *Node[K, V]) {
+	if !l.owns(mark) {
+		return
+	}
+
+	l.insertAfter(newNode, mark.prev)
+}
+
+// InsertAfter inserts a new node right after the mark.
+// The mark must be a node of the list; otherwise the list is left unchanged.
+func (l *List[K, V]) InsertAfter(newNode, mark *Node[K, V]) {
+	if !l.owns(mark) {
+		return
+	}
+
+	l.insertAfter(newNode, mark)
+}
+
+// MoveToFront moves the node to the front of the list. The node may currently belong
+// to this list, to another list or to no list at all.
+func (l *List[K, V]) MoveToFront(node *Node[K, V]) {
+	l.move(node, l.sentinel)
+}
+
+// MoveAfter moves the node right after the mark. The mark must be a node of the list
+// other than the node itself; otherwise the list is left unchanged.
+func (l *List[K, V]) MoveAfter(node, mark *Node[K, V]) {
+	if !l.owns(mark) || node == mark {
+		return
+	}
+
+	l.move(node, mark)
+}
+
+// move relinks the node right after the given node without leaving it detached in between.
+func (l *List[K, V]) move(node, bfr *Node[K, V]) {
+	if node.own == nil {
+		l.insertAfter(node, bfr)
+		return
+	}
+	if l.owns(node) && node.prev == bfr {
+		return
+	}
+
+	node.prev.next = node.next
+	node.next.prev = node.prev
+	node.own.compress().len--
+	l.insertAfter(node, bfr)
+}
+
+// insertAfter links the new node right after the given node, which is either
+// the sentinel or a node of the list.
+func (l *List[K, V]) insertAfter(newNode, bfr *Node[K, V]) {
 	newNode.prev = bfr
 	newNode.next = bfr.next
+	newNode.own = l.own
+	l.own.len++
 	bfr.next = newNode
-	if newNode.next != nil {
-		newNode.next.prev = newNode
-	} else {
-		l.sentinel.prev = newNode
-	}
+	newNode.next.prev = newNode
+}
+
+// Len returns the number of nodes in the list.
+//
+// O(1)
+func (l *List[K, V]) Len() int {
+	return l.own.len
 }
 
 // Last returns the last node in the list (the node before the sentinel).
@@ -57,6 +204,26 @@ func (l *List[K, V]) Last() *Node[K, V] {
 	return l.sentinel.prev
 }
 
+// Front returns the first node in the list, or nil if the list is empty.
+// Together with Back, PushFront, PushBack, InsertBefore, InsertAfter, Remove, Len and
+// the nil-terminated Node.Next and Node.Prev it mirrors the container/list API.
+func (l *List[K, V]) Front() *Node[K, V] {
+	if l.IsEmpty() {
+		return nil
+	}
+
+	return l.sentinel.next
+}
+
+// Back returns the last node in the list, or nil if the list is empty.
+func (l *List[K, V]) Back() *Node[K, V] {
+	if l.IsEmpty() {
+		return nil
+	}
+
+	return l.sentinel.prev
+}
+
 // First returns the first node in the list (the node right after the sentinel).
 func (l *List[K, V]) First() *Node[K, V] {
 	return l.sentinel.next
@@ -70,26 +237,289 @@ func (l *List[K, V]) IsEmpty() bool {
 
 // Untie removes the node from the list by updating the previous and next nodes' pointers
 // to bypass the current node. After calling this function, the node is "unlinked" from the list.
+// Calling Untie on a nil node, a detached node or a sentinel does nothing.
 func (n *Node[K, V]) Untie() {
+	if n == nil || n.own == nil {
+		return
+	}
+
 	n.next.prev = n.prev
 	n.prev.next = n.next
 	n.prev = nil
 	n.next = nil
+	n.own.compress().len--
+	n.own = nil
+}
+
+// Remove unties the node if it belongs to the list and reports whether it was removed.
+//
+// O(1)
+func (l *List[K, V]) Remove(node *Node[K, V]) bool {
+	if !l.owns(node) {
+		return false
+	}
+
+	node.Untie()
+	return true
+}
+
+// ContainsNode reports whether the node belongs to the list. It does not modify the list,
+// so it may be called under SyncList.DoRead.
+//
+// O(1), plus one step per SpliceBack the node went through that no modifying operation
+// has shortened since
+func (l *List[K, V]) ContainsNode(node *Node[K, V]) bool {
+	return l.contains(node)
+}
+
+// Find returns the first node from the front whose key and value match the predicate,
+// or nil if there is none.
+//
+// O(n)
+func (l *List[K, V]) Find(pred func(K, V) bool) *Node[K, V] {
+	for node := l.sentinel.next; node != l.sentinel; node = node.next {
+		if pred(node.Key, node.Value) {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// contains reports whether the node belongs to the list without modifying anything,
+// so read-only operations such as ContainsNode and Validate are safe under a read lock.
+func (l *List[K, V]) contains(node *Node[K, V]) bool {
+	return node != nil && node.own != nil && node.own.root() == l.own
+}
+
+// owns works like contains but also points the node straight at the list's token,
+// so later lookups of the node are O(1). Only operations modifying the list may call it.
+func (l *List[K, V]) owns(node *Node[K, V]) bool {
+	if node == nil || node.own == nil {
+		return false
+	}
+
+	node.own = node.own.compress()
+	return node.own == l.own
+}
+
+// Clear removes all nodes from the list. Every removed node is detached as if by Untie,
+// so stale pointers to former nodes cannot be used to corrupt the list.
+//
+// O(n)
+func (l *List[K, V]) Clear() {
+	for node := l.sentinel.next; node != l.sentinel; {
+		next := node.next
+		node.prev = nil
+		node.next = nil
+		node.own = nil
+		node = next
+	}
+
+	l.sentinel.next = l.sentinel
+	l.sentinel.prev = l.sentinel
+	l.own.len = 0
+}
+
+// Reverse reverses the order of the nodes in place, without allocating or moving nodes
+// between lists.
+//
+// O(n)
+func (l *List[K, V]) Reverse() {
+	node := l.sentinel
+	for {
+		node.next, node.prev = node.prev, node.next
+		node = node.prev
+		if node == l.sentinel {
+			return
+		}
+	}
+}
+
+// SortFunc sorts the list in place in ascending order as determined by cmp,
+// which returns a negative number when a < b, a positive number when a > b and zero otherwise.
+// The sort is stable: nodes comparing equal keep their relative order.
+// It is a bottom-up merge sort over the node links, so no slice is materialized.
+//
+// O(n * log(n))
+func (l *List[K, V]) SortFunc(cmp func(a, b *Node[K, V]) int) {
+	if l.own.len < 2 {
+		return
+	}
+
+	// Work on a nil-terminated singly linked chain and restore prev links afterwards.
+	head := l.sentinel.next
+	l.sentinel.prev.next = nil
+
+	for runSize := 1; ; runSize *= 2 {
+		left := head
+		head = nil
+		var tail *Node[K, V]
+		merges := 0
+
+		for left != nil {
+			merges++
+			right := left
+			leftSize := 0
+			for ; leftSize < runSize && right != nil; leftSize++ {
+				right = right.next
+			}
+			rightSize := runSize
+
+			for leftSize > 0 || (rightSize > 0 && right != nil) {
+				var next *Node[K, V]
+				switch {
+				case leftSize == 0:
+					next, right = right, right.next
+					rightSize--
+				case rightSize == 0 || right == nil || cmp(left, right) <= 0:
+					next, left = left, left.next
+					leftSize--
+				default:
+					next, right = right, right.next
+					rightSize--
+				}
+
+				if tail == nil {
+					head = next
+				} else {
+					tail.next = next
+				}
+				tail = next
+			}
+
+			left = right
+		}
+
+		tail.next = nil
+		if merges <= 1 {
+			break
+		}
+	}
+
+	prev := l.sentinel
+	for node := head; node != nil; node = node.next {
+		node.prev = prev
+		prev = node
+	}
+	prev.next = l.sentinel
+	l.sentinel.prev = prev
+	l.sentinel.next = head
+}
+
+// SpliceBack moves all nodes of the other list to the back of this list, leaving the other list empty.
+// Splicing a list into itself does nothing.
+//
+// O(1)
+func (l *List[K, V]) SpliceBack(other *List[K, V]) {
+	if other == l || other.IsEmpty() {
+		return
+	}
+
+	first, last := other.sentinel.next, other.sentinel.prev
+	first.prev = l.sentinel.prev
+	l.sentinel.prev.next = first
+	last.next = l.sentinel
+	l.sentinel.prev = last
+
+	l.own.len += other.own.len
+	other.own.parent = l.own
+	other.own = &owner{}
+	other.sentinel.next = other.sentinel
+	other.sentinel.prev = other.sentinel
 }
 
-// Next returns the next node in the list.
+// Next returns the next node in the list, or nil if the node is the last one or detached.
 func (n *Node[K, V]) Next() *Node[K, V] {
+	if n.next == nil || n.next.own == nil {
+		return nil
+	}
+
 	return n.next
 }
 
-// Prev returns the previous node in the list.
+// Prev returns the previous node in the list, or nil if the node is the first one or detached.
 func (n *Node[K, V]) Prev() *Node[K, V] {
+	if n.prev == nil || n.prev.own == nil {
+		return nil
+	}
+
 	return n.prev
 }
 
+// All returns the iterator over the keys and values of the list from front to back.
+// The current node may be removed or moved while iterating.
+func (l *List[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := l.sentinel.next; node != l.sentinel; {
+			next := node.next
+			if !yield(node.Key, node.Value) {
+				return
+			}
+			node = next
+		}
+	}
+}
+
+// Backward returns the iterator over the keys and values of the list from back to front.
+// The current node may be removed or moved while iterating.
+func (l *List[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := l.sentinel.prev; node != l.sentinel; {
+			prev := node.prev
+			if !yield(node.Key, node.Value) {
+				return
+			}
+			node = prev
+		}
+	}
+}
+
+// Validate walks the list and checks its structural invariants: prev/next symmetry,
+// membership of every node, reachability of the sentinel in both directions,
+// absence of cycles beyond the sentinel and agreement with Len.
+// It returns an error wrapping ErrCorrupted describing the first violation found.
+//
+// O(n)
+func (l *List[K, V]) Validate() error {
+	if l.sentinel == nil || l.own == nil {
+		return fmt.Errorf("%w: list is not initialized", ErrCorrupted)
+	}
+	if l.own.parent != nil {
+		return fmt.Errorf("%w: list token is not a root", ErrCorrupted)
+	}
+
+	count := 0
+	for node := l.sentinel; ; node = node.next {
+		if node.next == nil || node.prev == nil {
+			return fmt.Errorf("%w: nil link at position %d", ErrCorrupted, count)
+		}
+		if node.next.prev != node || node.prev.next != node {
+			return fmt.Errorf("%w: asymmetric links at position %d", ErrCorrupted, count)
+		}
+		if node.next == l.sentinel {
+			break
+		}
+
+		count++
+		if count > l.own.len {
+			return fmt.Errorf("%w: more than %d nodes before the sentinel", ErrCorrupted, l.own.len)
+		}
+		if !l.contains(node.next) {
+			return fmt.Errorf("%w: node at position %d belongs to another list", ErrCorrupted, count)
+		}
+	}
+
+	if count != l.own.len {
+		return fmt.Errorf("%w: %d nodes, but Len is %d", ErrCorrupted, count, l.own.len)
+	}
+
+	return nil
+}
+
 // Iterator represents an iterator for the List.
 // It provides methods to traverse the list in both forward and backward directions.
-type Iterator[K comparable, V any] struct {
+type Iterator[K, V any] struct {
 	current *Node[K, V]
 }
 
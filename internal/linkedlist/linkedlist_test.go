@@ -0,0 +1,436 @@
+package linkedlist
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLen(t *testing.T) {
+	t.Parallel()
+
+	list := NewList[int, int]()
+	require.Equal(t, 0, list.Len())
+
+	first := NewNode(1, 10)
+	second := NewNode(2, 20)
+	list.AddFrontOrAfter(first)
+	list.AddFrontOrAfter(second, first)
+	require.Equal(t, 2, list.Len())
+	require.Equal(t, []int{1, 2}, keys(list))
+
+	first.Untie()
+	require.Equal(t, 1, list.Len())
+
+	second.Untie()
+	require.Equal(t, 0, list.Len())
+	require.True(t, list.IsEmpty())
+}
+
+func TestPushAndInsertBefore(t *testing.T) {
+	t.Parallel()
+
+	list := NewList[int, int]()
+	two := NewNode(2, 0)
+
+	list.PushBack(two)
+	list.PushFront(NewNode(1, 0))
+	list.PushBack(NewNode(4, 0))
+	list.InsertBefore(NewNode(3, 0), list.Last())
+	list.InsertBefore(NewNode(0, 0), list.First())
+	require.Equal(t, []int{0, 1, 2, 3, 4}, keys(list))
+	require.Equal(t, 5, list.Len())
+
+	other := NewList[int, int]()
+	other.InsertBefore(NewNode(5, 0), two)
+	require.True(t, other.IsEmpty())
+	require.Equal(t, 5, list.Len())
+}
+
+func TestMove(t *testing.T) {
+	t.Parallel()
+
+	list := NewList[int, int]()
+	one, two, three := NewNode(1, 0), NewNode(2, 0), NewNode(3, 0)
+	list.PushBack(one)
+	list.PushBack(two)
+	list.PushBack(three)
+
+	list.MoveToFront(three)
+	require.Equal(t, []int{3, 1, 2}, keys(list))
+
+	list.MoveAfter(three, two)
+	require.Equal(t, []int{1, 2, 3}, keys(list))
+
+	list.MoveAfter(one, one)
+	require.Equal(t, []int{1, 2, 3}, keys(list))
+
+	other := NewList[int, int]()
+	other.MoveToFront(two)
+	require.Equal(t, []int{1, 3}, keys(list))
+	require.Equal(t, []int{2}, keys(other))
+	require.Equal(t, 2, list.Len())
+	require.Equal(t, 1, other.Len())
+
+	detached := NewNode(4, 0)
+	other.MoveToFront(detached)
+	require.Equal(t, []int{4, 2}, keys(other))
+}
+
+func TestSafeRemoval(t *testing.T) {
+	t.Parallel()
+
+	list := NewList[int, int]()
+	other := NewList[int, int]()
+	one, two := NewNode(1, 0), NewNode(2, 0)
+	list.PushBack(one)
+	list.PushBack(two)
+
+	require.False(t, other.Remove(one))
+	require.False(t, list.Remove(nil))
+	require.True(t, list.Remove(one))
+	require.False(t, list.Remove(one))
+
+	one.Untie()
+	list.sentinel.Untie()
+	(*Node[int, int])(nil).Untie()
+
+	require.Equal(t, []int{2}, keys(list))
+	require.Equal(t, 1, list.Len())
+}
+
+func TestAllAndBackward(t *testing.T) {
+	t.Parallel()
+
+	list := NewList[int, string]()
+	for i, v := range []string{"a", "b", "c"} {
+		list.PushBack(NewNode(i, v))
+	}
+
+	values := make([]string, 0)
+	for _, v := range list.All() {
+		values = append(values, v)
+	}
+	require.Equal(t, []string{"a", "b", "c"}, values)
+
+	backward := make([]int, 0)
+	for k := range list.Backward() {
+		backward = append(backward, k)
+		if k == 1 {
+			break
+		}
+	}
+	require.Equal(t, []int{2, 1}, backward)
+}
+
+func TestSpliceBack(t *testing.T) {
+	t.Parallel()
+
+	first, second, third := NewList[int, int](), NewList[int, int](), NewList[int, int]()
+	moved := NewNode(3, 0)
+	first.PushBack(NewNode(1, 0))
+	second.PushBack(NewNode(2, 0))
+	second.PushBack(moved)
+	third.PushBack(NewNode(4, 0))
+
+	first.SpliceBack(second)
+	require.Equal(t, []int{1, 2, 3}, keys(first))
+	require.Equal(t, 3, first.Len())
+	require.True(t, second.IsEmpty())
+	require.Equal(t, 0, second.Len())
+
+	third.SpliceBack(first)
+	require.Equal(t, []int{4, 1, 2, 3}, keys(third))
+	require.Equal(t, 4, third.Len())
+
+	require.False(t, second.Remove(moved))
+	require.False(t, first.Remove(moved))
+	require.True(t, third.Remove(moved))
+	require.Equal(t, 3, third.Len())
+
+	second.PushBack(NewNode(5, 0))
+	first.SpliceBack(first)
+	third.SpliceBack(first)
+	require.Equal(t, []int{5}, keys(second))
+	require.Equal(t, []int{4, 1, 2}, keys(third))
+}
+
+func TestClear(t *testing.T) {
+	t.Parallel()
+
+	list := NewList[int, int]()
+	node := NewNode(1, 0)
+	list.PushBack(node)
+	list.PushBack(NewNode(2, 0))
+
+	list.Clear()
+	require.True(t, list.IsEmpty())
+	require.Equal(t, 0, list.Len())
+	require.Nil(t, node.Next())
+	require.False(t, list.Remove(node))
+
+	node.Untie()
+	list.PushBack(node)
+	require.Equal(t, []int{1}, keys(list))
+}
+
+func TestFindAndContainsNode(t *testing.T) {
+	t.Parallel()
+
+	list := NewList[int, string]()
+	other := NewList[int, string]()
+	for i, v := range []string{"a", "b", "b"} {
+		list.PushBack(NewNode(i, v))
+	}
+
+	found := list.Find(func(_ int, v string) bool { return v == "b" })
+	require.NotNil(t, found)
+	require.Equal(t, 1, found.Key)
+	require.Nil(t, list.Find(func(_ int, v string) bool { return v == "c" }))
+
+	require.True(t, list.ContainsNode(found))
+	require.False(t, other.ContainsNode(found))
+	require.False(t, list.ContainsNode(list.sentinel))
+	require.False(t, list.ContainsNode(nil))
+}
+
+func TestContainerListFacade(t *testing.T) {
+	t.Parallel()
+
+	list := NewList[int, int]()
+	require.Nil(t, list.Front())
+	require.Nil(t, list.Back())
+
+	list.PushBack(NewNode(1, 0))
+	list.PushBack(NewNode(3, 0))
+	list.InsertAfter(NewNode(2, 0), list.Front())
+	list.InsertAfter(NewNode(4, 0), NewNode(5, 0))
+
+	forward := make([]int, 0)
+	for node := list.Front(); node != nil; node = node.Next() {
+		forward = append(forward, node.Key)
+	}
+	require.Equal(t, []int{1, 2, 3}, forward)
+
+	backward := make([]int, 0)
+	for node := list.Back(); node != nil; node = node.Prev() {
+		backward = append(backward, node.Key)
+	}
+	require.Equal(t, []int{3, 2, 1}, backward)
+	require.Equal(t, 3, list.Len())
+}
+
+func TestNonComparableKeys(t *testing.T) {
+	t.Parallel()
+
+	list := NewList[[]byte, int]()
+	list.PushBack(NewNode([]byte("a"), 1))
+	list.PushBack(NewNode([]byte("b"), 2))
+
+	require.Equal(t, [][]byte{[]byte("a"), []byte("b")}, keys(list))
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	list := NewList[int, int]()
+	for i := range 5 {
+		list.PushBack(NewNode(i, 0))
+	}
+	require.NoError(t, list.Validate())
+
+	other := NewList[int, int]()
+	other.SpliceBack(list)
+	require.NoError(t, list.Validate())
+	require.NoError(t, other.Validate())
+
+	other.Front().Next().prev = other.sentinel
+	require.ErrorIs(t, other.Validate(), ErrCorrupted)
+
+	miscounted := NewList[int, int]()
+	first, second := NewNode(1, 0), NewNode(2, 0)
+	miscounted.PushBack(first)
+	miscounted.PushBack(second)
+	miscounted.own.len = 1
+	require.ErrorIs(t, miscounted.Validate(), ErrCorrupted)
+}
+
+func TestSyncList(t *testing.T) {
+	t.Parallel()
+
+	list := NewSyncList[int, int]()
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				node := NewNode(i*100+j, 0)
+				list.PushBack(node)
+				list.MoveToFront(node)
+				_ = list.Len()
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 800, list.Len())
+	list.DoRead(func(l *List[int, int]) {
+		require.NoError(t, l.Validate())
+	})
+
+	back := list.PopBack()
+	require.NotNil(t, back)
+	require.False(t, list.Remove(back))
+
+	keys, _ := list.Snapshot()
+	require.Len(t, keys, 799)
+}
+
+func TestSyncListConcurrentReads(t *testing.T) {
+	t.Parallel()
+
+	list := NewSyncList[int, int]()
+	spliced := NewList[int, int]()
+	nodes := make([]*Node[int, int], 0, 10)
+	for i := range 10 {
+		node := NewNode(i, i)
+		spliced.PushBack(node)
+		nodes = append(nodes, node)
+	}
+	list.Do(func(l *List[int, int]) {
+		for range 3 {
+			other := NewList[int, int]()
+			other.SpliceBack(spliced)
+			spliced = other
+		}
+		l.SpliceBack(spliced)
+	})
+
+	errs := make([]error, 4)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			list.DoRead(func(l *List[int, int]) {
+				for _, node := range nodes {
+					if !l.ContainsNode(node) {
+						errs[i] = fmt.Errorf("node %d is not contained", node.Key)
+						return
+					}
+				}
+				errs[i] = l.Validate()
+			})
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func TestArena(t *testing.T) {
+	arena := NewArena[int, string](2)
+	first, second := NewListWithArena(arena), NewListWithArena(arena)
+
+	a := first.NewNode(1, "a")
+	b := first.NewNode(2, "b")
+	c := first.NewNode(3, "c")
+	first.PushBack(a)
+	first.PushBack(b)
+	second.PushBack(c)
+	second.MoveToFront(b)
+
+	second.Free(b)
+	require.Equal(t, []int{1}, keys(first))
+	require.Equal(t, []int{3}, keys(second))
+	require.Empty(t, b.Value)
+
+	require.PanicsWithValue(t, "Node is already freed.", func() { second.Free(b) })
+
+	reused := second.NewNode(4, "d")
+	require.Same(t, b, reused)
+	require.Nil(t, reused.Prev())
+	second.PushBack(reused)
+	require.Equal(t, []int{3, 4}, keys(second))
+	require.NoError(t, second.Validate())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		node := first.NewNode(5, "e")
+		first.PushBack(node)
+		first.Free(node)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestNewListFrom(t *testing.T) {
+	t.Parallel()
+
+	fromSlice := NewListFromSlice([]Pair[int, string]{{1, "a"}, {2, "b"}, {3, "c"}})
+	require.Equal(t, []int{1, 2, 3}, keys(fromSlice))
+	require.Equal(t, 3, fromSlice.Len())
+
+	fromSeq := NewListFrom(fromSlice.Backward())
+	require.Equal(t, []int{3, 2, 1}, keys(fromSeq))
+	require.NoError(t, fromSeq.Validate())
+
+	require.True(t, NewListFromSlice[int, int](nil).IsEmpty())
+}
+
+func TestReverse(t *testing.T) {
+	t.Parallel()
+
+	list := NewListFromSlice([]Pair[int, int]{{1, 0}, {2, 0}, {3, 0}})
+	list.Reverse()
+	require.Equal(t, []int{3, 2, 1}, keys(list))
+	require.NoError(t, list.Validate())
+
+	empty := NewList[int, int]()
+	empty.Reverse()
+	require.True(t, empty.IsEmpty())
+	require.NoError(t, empty.Validate())
+}
+
+func TestSortFunc(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{0, 1, 2, 3, 7, 100, 1000} {
+		pairs := make([]Pair[int, int], size)
+		for i := range pairs {
+			pairs[i] = Pair[int, int]{Key: rand.N(10), Value: i}
+		}
+
+		list := NewListFromSlice(pairs)
+		list.SortFunc(func(a, b *Node[int, int]) int {
+			return cmp.Compare(a.Key, b.Key)
+		})
+
+		slices.SortStableFunc(pairs, func(a, b Pair[int, int]) int {
+			return cmp.Compare(a.Key, b.Key)
+		})
+
+		sorted := make([]Pair[int, int], 0, size)
+		for k, v := range list.All() {
+			sorted = append(sorted, Pair[int, int]{Key: k, Value: v})
+		}
+		require.Equal(t, pairs, sorted)
+		require.NoError(t, list.Validate())
+	}
+}
+
+func keys[K, V any](list *List[K, V]) []K {
+	result := make([]K, 0)
+	for it := list.Begin(); !it.Equals(list.End()); it = it.Next() {
+		result = append(result, it.Value().Key)
+	}
+
+	return result
+}
@@ -0,0 +1,99 @@
+package linkedlist
+
+import "sync"
+
+// SyncList is a List guarded by a read-write mutex, safe for concurrent use.
+// Single-step operations lock internally; multi-step operations that must be atomic
+// (e.g. find a node, then move it) go through Do or DoRead.
+//
+// Nodes passed to SyncList must not be modified through the unsynchronized List API
+// while other goroutines use the SyncList.
+type SyncList[K, V any] struct {
+	mu   sync.RWMutex
+	list *List[K, V]
+}
+
+// NewSyncList creates and initializes a new concurrency-safe doubly linked list.
+func NewSyncList[K, V any]() *SyncList[K, V] {
+	return &SyncList[K, V]{list: NewList[K, V]()}
+}
+
+// Do runs fn with exclusive access to the underlying list.
+// The list must not be retained or used after fn returns.
+func (s *SyncList[K, V]) Do(fn func(l *List[K, V])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(s.list)
+}
+
+// DoRead runs fn with shared read access to the underlying list.
+// fn may only call methods that do not modify the list, such as Len, Front, Back, Find,
+// ContainsNode, All and Validate, and the list must not be retained after fn returns.
+func (s *SyncList[K, V]) DoRead(fn func(l *List[K, V])) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fn(s.list)
+}
+
+// Len returns the number of nodes in the list.
+func (s *SyncList[K, V]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list.Len()
+}
+
+// PushFront inserts a new node at the front of the list.
+func (s *SyncList[K, V]) PushFront(newNode *Node[K, V]) {
+	s.Do(func(l *List[K, V]) { l.PushFront(newNode) })
+}
+
+// PushBack inserts a new node at the back of the list.
+func (s *SyncList[K, V]) PushBack(newNode *Node[K, V]) {
+	s.Do(func(l *List[K, V]) { l.PushBack(newNode) })
+}
+
+// MoveToFront moves a node of this list to its front.
+func (s *SyncList[K, V]) MoveToFront(node *Node[K, V]) {
+	s.Do(func(l *List[K, V]) {
+		if l.owns(node) {
+			l.MoveToFront(node)
+		}
+	})
+}
+
+// Remove unties the node if it belongs to the list and reports whether it was removed.
+func (s *SyncList[K, V]) Remove(node *Node[K, V]) bool {
+	removed := false
+	s.Do(func(l *List[K, V]) { removed = l.Remove(node) })
+
+	return removed
+}
+
+// PopBack removes and returns the last node, or nil if the list is empty.
+func (s *SyncList[K, V]) PopBack() *Node[K, V] {
+	var node *Node[K, V]
+	s.Do(func(l *List[K, V]) {
+		node = l.Back()
+		node.Untie()
+	})
+
+	return node
+}
+
+// Snapshot returns copies of the keys and values from front to back.
+func (s *SyncList[K, V]) Snapshot() ([]K, []V) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]K, 0, s.list.Len())
+	values := make([]V, 0, s.list.Len())
+	for k, v := range s.list.All() {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+
+	return keys, values
+}
@@ -0,0 +1,104 @@
+package statsd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"lfucache/internal/lfu"
+)
+
+// ErrInvalidInterval is returned by Run for a flush interval that is not positive.
+var ErrInvalidInterval = errors.New("invalid flush interval")
+
+// Snapshot represents the cache state reported by one flush.
+type Snapshot struct {
+	Stats    lfu.Stats
+	Size     int
+	Capacity int
+}
+
+// Sink represents a StatsD sink for cache metrics. Every flush writes one packet with
+// the counters accumulated since the previous flush (hits, misses, evictions, rejections
+// and replacements) and the size and capacity gauges, each name prefixed with the prefix.
+// Tags, given as "key:value", are appended in the DogStatsD format.
+//
+// The writer is typically a UDP connection obtained with net.Dial("udp", "localhost:8125").
+type Sink struct {
+	w      io.Writer
+	prefix string
+	tags   string
+	prev   lfu.Stats
+}
+
+// New initializes the sink writing to w.
+//
+// Returns:
+//   - A pointer to a new Sink instance.
+func New(w io.Writer, prefix string, tags ...string) *Sink {
+	sink := &Sink{w: w, prefix: prefix}
+	if len(tags) > 0 {
+		sink.tags = "|#" + strings.Join(tags, ",")
+	}
+
+	return sink
+}
+
+// Flush writes the metrics of the snapshot. If the counters went down since the previous
+// flush, e.g. after ResetStats, the snapshot counters are reported as they are.
+func (s *Sink) Flush(snapshot Snapshot) error {
+	delta := snapshot.Stats.Delta(s.prev)
+	if delta.Hits < 0 || delta.Misses < 0 || delta.Evictions < 0 || delta.Rejections < 0 || delta.Replacements < 0 {
+		delta = snapshot.Stats
+	}
+
+	var packet strings.Builder
+	s.metric(&packet, "hits", delta.Hits, "c")
+	s.metric(&packet, "misses", delta.Misses, "c")
+	s.metric(&packet, "evictions", delta.Evictions, "c")
+	s.metric(&packet, "rejections", delta.Rejections, "c")
+	s.metric(&packet, "replacements", delta.Replacements, "c")
+	s.metric(&packet, "size", snapshot.Size, "g")
+	s.metric(&packet, "capacity", snapshot.Capacity, "g")
+
+	if _, err := io.WriteString(s.w, packet.String()); err != nil {
+		return err
+	}
+
+	s.prev = snapshot.Stats
+	return nil
+}
+
+// Run flushes a snapshot every interval until the context is done or a write fails,
+// and returns the reason it stopped. The cache is not safe for concurrent use,
+// so snapshot must read it the same way the rest of the program does, e.g. under its lock.
+// Returns an error wrapping ErrInvalidInterval right away if the interval is not positive.
+func (s *Sink) Run(ctx context.Context, interval time.Duration, snapshot func() Snapshot) error {
+	if interval <= 0 {
+		return fmt.Errorf("%w: %v must be positive", ErrInvalidInterval, interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := s.Flush(snapshot()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Sink) metric(packet *strings.Builder, name string, value int, kind string) {
+	if packet.Len() > 0 {
+		packet.WriteByte('\n')
+	}
+	fmt.Fprintf(packet, "%s%s:%d|%s%s", s.prefix, name, value, kind, s.tags)
+}
@@ -0,0 +1,76 @@
+package statsd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"lfucache/internal/lfu"
+)
+
+func TestFlushReportsDeltas(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	sink := New(&out, "cache.")
+
+	require.NoError(t, sink.Flush(Snapshot{Stats: lfu.Stats{Hits: 3, Misses: 1}, Size: 1, Capacity: 5}))
+	require.Equal(t, "cache.hits:3|c\ncache.misses:1|c\ncache.evictions:0|c\ncache.rejections:0|c\n"+
+		"cache.replacements:0|c\ncache.size:1|g\ncache.capacity:5|g", out.String())
+
+	out.Reset()
+	require.NoError(t, sink.Flush(Snapshot{Stats: lfu.Stats{Hits: 5, Misses: 1, Evictions: 2}, Size: 5, Capacity: 5}))
+	require.Contains(t, out.String(), "cache.hits:2|c\ncache.misses:0|c\ncache.evictions:2|c")
+
+	out.Reset()
+	require.NoError(t, sink.Flush(Snapshot{Stats: lfu.Stats{Hits: 1}, Size: 5, Capacity: 5}))
+	require.Contains(t, out.String(), "cache.hits:1|c\n")
+}
+
+func TestFlushWithTags(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	sink := New(&out, "", "env:prod", "cache:users")
+
+	require.NoError(t, sink.Flush(Snapshot{Size: 2}))
+	require.Contains(t, out.String(), "size:2|g|#env:prod,cache:users")
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var out strings.Builder
+	flushes := 0
+	err := New(&out, "").Run(ctx, time.Millisecond, func() Snapshot {
+		flushes++
+		return Snapshot{}
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Positive(t, flushes)
+
+	err = New(failingWriter{}, "").Run(context.Background(), time.Millisecond, func() Snapshot {
+		return Snapshot{}
+	})
+	require.ErrorContains(t, err, "connection refused")
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		err = New(&out, "").Run(context.Background(), interval, func() Snapshot {
+			return Snapshot{}
+		})
+		require.ErrorIs(t, err, ErrInvalidInterval)
+	}
+}
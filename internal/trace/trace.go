@@ -0,0 +1,115 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+var ErrMalformed = errors.New("malformed trace")
+
+// Trace represents a cache access trace read from a stream. Keys yields the accessed keys
+// in order and stops at the end of the stream or at the first malformed record;
+// Err then reports what stopped it. A trace can be iterated only once.
+type Trace[K comparable] struct {
+	next func(yield func(K) bool) error
+	err  error
+}
+
+// Keys returns the sequence of accessed keys, e.g. to pass to compare.Run.
+func (t *Trace[K]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if t.next == nil {
+			return
+		}
+
+		next := t.next
+		t.next = nil
+		t.err = next(yield)
+	}
+}
+
+// Err returns the first read or parse error, or nil if the trace was read to the end
+// or the iteration was stopped early.
+func (t *Trace[K]) Err() error {
+	return t.err
+}
+
+// ARC reads a trace in the format used by Megiddo and Modha for ARC: every line holds
+// the starting block, the number of blocks, an ignored field and the request number,
+// separated by spaces, and accesses the blocks start, start+1, ..., start+count-1.
+func ARC(r io.Reader) *Trace[int64] {
+	return &Trace[int64]{next: func(yield func(int64) bool) error {
+		scanner := bufio.NewScanner(r)
+		for line := 1; scanner.Scan(); line++ {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) == 0 {
+				continue
+			}
+			if len(fields) < 2 {
+				return fmt.Errorf("%w: arc line %d: %d fields, want 4", ErrMalformed, line, len(fields))
+			}
+
+			start, err := strconv.ParseInt(fields[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w: arc line %d: start block: %w", ErrMalformed, line, err)
+			}
+			count, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil || count < 0 {
+				return fmt.Errorf("%w: arc line %d: invalid block count %q", ErrMalformed, line, fields[1])
+			}
+
+			for block := start; block < start+count; block++ {
+				if !yield(block) {
+					return nil
+				}
+			}
+		}
+
+		return scanner.Err()
+	}}
+}
+
+// Twitter reads a trace in the format of the Twitter production cache traces: CSV records
+// of timestamp, anonymized key, key size, value size, client id, operation and TTL.
+// Every request accesses its key, regardless of the operation.
+func Twitter(r io.Reader) *Trace[string] {
+	return CSV(r, 1)
+}
+
+// CSV reads a trace of CSV records, taking the accessed key from the column keyCol (zero-based).
+// A header row is not expected. A negative keyCol yields no keys and an error wrapping ErrMalformed.
+func CSV(r io.Reader, keyCol int) *Trace[string] {
+	return &Trace[string]{next: func(yield func(string) bool) error {
+		if keyCol < 0 {
+			return fmt.Errorf("%w: column %d must not be negative", ErrMalformed, keyCol)
+		}
+
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.ReuseRecord = true
+
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrMalformed, err)
+			}
+			if keyCol >= len(record) {
+				line, _ := reader.FieldPos(0)
+				return fmt.Errorf("%w: csv line %d: %d fields, want column %d", ErrMalformed, line, len(record), keyCol)
+			}
+
+			if !yield(record[keyCol]) {
+				return nil
+			}
+		}
+	}}
+}
@@ -0,0 +1,72 @@
+package trace
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"lfucache/internal/compare"
+)
+
+func TestARC(t *testing.T) {
+	t.Parallel()
+
+	trace := ARC(strings.NewReader("10 3 0 1\n\n42 1 0 2\n11 0 0 3\n"))
+	require.Equal(t, []int64{10, 11, 12, 42}, slices.Collect(trace.Keys()))
+	require.NoError(t, trace.Err())
+
+	require.Empty(t, slices.Collect(trace.Keys()))
+}
+
+func TestARCMalformed(t *testing.T) {
+	t.Parallel()
+
+	trace := ARC(strings.NewReader("10 1 0 1\nx 1 0 2\n"))
+	require.Equal(t, []int64{10}, slices.Collect(trace.Keys()))
+	require.ErrorIs(t, trace.Err(), ErrMalformed)
+	require.ErrorContains(t, trace.Err(), "line 2")
+}
+
+func TestTwitter(t *testing.T) {
+	t.Parallel()
+
+	input := "0,key-a,5,100,1,get,0\n1,key-b,5,200,2,set,3600\n2,key-a,5,100,1,gets,0\n"
+	trace := Twitter(strings.NewReader(input))
+	require.Equal(t, []string{"key-a", "key-b", "key-a"}, slices.Collect(trace.Keys()))
+	require.NoError(t, trace.Err())
+}
+
+func TestCSV(t *testing.T) {
+	t.Parallel()
+
+	trace := CSV(strings.NewReader("a\nb,c\n"), 1)
+	require.Empty(t, slices.Collect(trace.Keys()))
+	require.ErrorIs(t, trace.Err(), ErrMalformed)
+
+	trace = CSV(strings.NewReader("a,b\n"), -1)
+	require.Empty(t, slices.Collect(trace.Keys()))
+	require.ErrorIs(t, trace.Err(), ErrMalformed)
+}
+
+func TestEarlyStop(t *testing.T) {
+	t.Parallel()
+
+	trace := CSV(strings.NewReader("a\nb\nc\n"), 0)
+	for key := range trace.Keys() {
+		require.Equal(t, "a", key)
+		break
+	}
+	require.NoError(t, trace.Err())
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	trace := ARC(strings.NewReader("0 10 0 1\n0 10 0 2\n"))
+	results := compare.Run(trace.Keys(), compare.LFU[int64](5, 10)...)
+	require.NoError(t, trace.Err())
+	require.Equal(t, 10, results[1].Hits)
+	require.Equal(t, 20, results[0].Misses)
+}
@@ -0,0 +1,147 @@
+package workload
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+)
+
+var ErrInvalidConfig = errors.New("invalid workload config")
+
+// Distribution represents how keys are picked from the working set.
+type Distribution string
+
+const (
+	// Uniform picks every key with the same probability.
+	Uniform Distribution = "uniform"
+	// Zipf picks key i with probability proportional to 1/(i+1)^ZipfS.
+	Zipf Distribution = "zipf"
+	// Hotspot picks one of the first HotKeys share of keys with probability HotProb,
+	// and any other key otherwise.
+	Hotspot Distribution = "hotspot"
+)
+
+// Config represents the parameters of a generated workload. Keys are ints in [0, Keys).
+type Config struct {
+	Keys         int
+	Distribution Distribution
+	ZipfS        float64 // Zipf skew, must be > 1.
+	HotKeys      float64 // Hotspot: share of keys that are hot, within (0, 1].
+	HotProb      float64 // Hotspot: probability of accessing a hot key, within [0, 1].
+
+	// Every ShiftEvery accesses the working set moves by ShiftBy keys, wrapping around,
+	// so previously hot keys cool down. Zero disables shifting. Neither may be negative.
+	ShiftEvery int
+	ShiftBy    int
+
+	// Every ScanEvery accesses a scan of ScanLength consecutive keys is emitted,
+	// continuing where the previous scan stopped. Zero disables scans.
+	ScanEvery  int
+	ScanLength int
+
+	Seed uint64
+}
+
+// Generator represents an endless sequence of keys following a Config.
+// It is not safe for concurrent use; create one generator per goroutine.
+type Generator struct {
+	cfg  Config
+	rng  *rand.Rand
+	zipf *rand.Zipf
+
+	accesses int // accesses generated outside scans
+	shift    int
+	scanLeft int // keys left in the current scan
+	scanPos  int
+}
+
+// New initializes the generator, returning an error wrapping ErrInvalidConfig
+// if the configuration is inconsistent.
+func New(cfg Config) (*Generator, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed^0x9e3779b97f4a7c15))
+	g := &Generator{cfg: cfg, rng: rng}
+	if cfg.Distribution == Zipf {
+		g.zipf = rand.NewZipf(rng, cfg.ZipfS, 1, uint64(cfg.Keys-1))
+	}
+
+	return g, nil
+}
+
+func (cfg Config) validate() error {
+	switch {
+	case cfg.Keys <= 0:
+		return fmt.Errorf("%w: keys %d must be positive", ErrInvalidConfig, cfg.Keys)
+	case cfg.ShiftEvery < 0 || cfg.ShiftBy < 0 || cfg.ScanEvery < 0 || cfg.ScanLength < 0:
+		return fmt.Errorf("%w: shift and scan settings must not be negative", ErrInvalidConfig)
+	}
+
+	switch cfg.Distribution {
+	case Uniform:
+	case Zipf:
+		if !(cfg.ZipfS > 1) {
+			return fmt.Errorf("%w: zipf skew %v must be > 1", ErrInvalidConfig, cfg.ZipfS)
+		}
+	case Hotspot:
+		if !(cfg.HotKeys > 0 && cfg.HotKeys <= 1) || !(cfg.HotProb >= 0 && cfg.HotProb <= 1) {
+			return fmt.Errorf("%w: hot keys %v must be within (0, 1] and hot probability %v within [0, 1]",
+				ErrInvalidConfig, cfg.HotKeys, cfg.HotProb)
+		}
+	default:
+		return fmt.Errorf("%w: unknown distribution %q", ErrInvalidConfig, cfg.Distribution)
+	}
+
+	return nil
+}
+
+// Next returns the next key.
+func (g *Generator) Next() int {
+	if g.scanLeft > 0 {
+		g.scanLeft--
+		key := g.scanPos
+		g.scanPos = (g.scanPos + 1) % g.cfg.Keys
+		return key
+	}
+
+	key := (g.pick() + g.shift) % g.cfg.Keys
+	g.accesses++
+	if g.cfg.ShiftEvery > 0 && g.accesses%g.cfg.ShiftEvery == 0 {
+		g.shift = (g.shift + g.cfg.ShiftBy) % g.cfg.Keys
+	}
+	if g.cfg.ScanEvery > 0 && g.accesses%g.cfg.ScanEvery == 0 {
+		g.scanLeft = g.cfg.ScanLength
+	}
+
+	return key
+}
+
+// Keys returns a sequence of the next n keys.
+func (g *Generator) Keys(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for range n {
+			if !yield(g.Next()) {
+				return
+			}
+		}
+	}
+}
+
+// pick returns a key of the unshifted working set.
+func (g *Generator) pick() int {
+	switch g.cfg.Distribution {
+	case Zipf:
+		return int(g.zipf.Uint64())
+	case Hotspot:
+		hot := max(1, int(float64(g.cfg.Keys)*g.cfg.HotKeys))
+		if hot >= g.cfg.Keys || g.rng.Float64() < g.cfg.HotProb {
+			return g.rng.IntN(hot)
+		}
+		return hot + g.rng.IntN(g.cfg.Keys-hot)
+	default:
+		return g.rng.IntN(g.cfg.Keys)
+	}
+}
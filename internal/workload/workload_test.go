@@ -0,0 +1,82 @@
+package workload
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeterministicAndInRange(t *testing.T) {
+	t.Parallel()
+
+	for _, dist := range []Distribution{Uniform, Zipf, Hotspot} {
+		cfg := Config{Keys: 100, Distribution: dist, ZipfS: 1.2, HotKeys: 0.1, HotProb: 0.9, Seed: 7}
+
+		first, err := New(cfg)
+		require.NoError(t, err)
+		second, err := New(cfg)
+		require.NoError(t, err)
+
+		keys := slices.Collect(first.Keys(1_000))
+		require.Equal(t, keys, slices.Collect(second.Keys(1_000)))
+		for _, key := range keys {
+			require.GreaterOrEqual(t, key, 0)
+			require.Less(t, key, 100)
+		}
+	}
+}
+
+func TestSkew(t *testing.T) {
+	t.Parallel()
+
+	for _, cfg := range []Config{
+		{Keys: 1_000, Distribution: Zipf, ZipfS: 1.5},
+		{Keys: 1_000, Distribution: Hotspot, HotKeys: 0.01, HotProb: 0.9},
+	} {
+		gen, err := New(cfg)
+		require.NoError(t, err)
+
+		hot := 0
+		for key := range gen.Keys(10_000) {
+			if key < 10 {
+				hot++
+			}
+		}
+		require.Greater(t, hot, 5_000, cfg.Distribution)
+	}
+}
+
+func TestWorkingSetShift(t *testing.T) {
+	t.Parallel()
+
+	gen, err := New(Config{Keys: 100, Distribution: Hotspot, HotKeys: 0.01, HotProb: 1, ShiftEvery: 3, ShiftBy: 10})
+	require.NoError(t, err)
+
+	require.Equal(t, []int{0, 0, 0, 10, 10, 10, 20}, slices.Collect(gen.Keys(7)))
+}
+
+func TestScanPhases(t *testing.T) {
+	t.Parallel()
+
+	gen, err := New(Config{Keys: 10, Distribution: Hotspot, HotKeys: 0.1, HotProb: 1, ScanEvery: 2, ScanLength: 3})
+	require.NoError(t, err)
+
+	require.Equal(t, []int{0, 0, 0, 1, 2, 0, 0, 3, 4, 5}, slices.Collect(gen.Keys(10)))
+}
+
+func TestInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, cfg := range []Config{
+		{Keys: 0, Distribution: Uniform},
+		{Keys: 10, Distribution: "pareto"},
+		{Keys: 10, Distribution: Zipf, ZipfS: 1},
+		{Keys: 10, Distribution: Hotspot, HotKeys: 0, HotProb: 0.5},
+		{Keys: 10, Distribution: Uniform, ScanEvery: -1},
+		{Keys: 10, Distribution: Uniform, ShiftEvery: 1, ShiftBy: -3},
+	} {
+		_, err := New(cfg)
+		require.ErrorIs(t, err, ErrInvalidConfig)
+	}
+}